@@ -0,0 +1,250 @@
+package ringbuffer
+
+import (
+	"strconv"
+	"sync"
+)
+
+// BoolRing is a ring buffer of booleans backed by a bitset, storing one bit per value
+// instead of the one byte per value of a RingBuffer[bool]. It is meant for long
+// histories of pass/fail flags, such as health checks.
+//
+// BoolRing offers the same API as RingBuffer, and additionally maintains the number of
+// true values and the run of equal values at the tail (newest end) incrementally, so
+// CountTrue(), Ratio() and TailRun() are O(1).
+type BoolRing struct {
+	// bits contains all values, one bit per value, including undefined elements which
+	// are false
+	bits         []uint64
+	mut          sync.RWMutex
+	capacity     int    // Total size of the buffer
+	elementCount int    // Number of values stored within the buffer
+	writeIndex   int    // The next index to write into the buffer when Write() is called
+	overwrites   uint64 // Number of values overwritten because the buffer was full
+	trueCount    int    // Number of true values stored within the buffer
+	runValue     bool   // Value of the newest element
+	runLength    int    // Number of consecutive elements equal to runValue at the tail
+}
+
+// NewBoolRing creates a new bitset-backed boolean ring buffer with a fixed, zero-indexed
+// capacity
+func NewBoolRing(capacity int) (*BoolRing, error) {
+	if capacity <= 0 {
+		return nil, ErrCapacityNegativeOrZero
+	}
+	return &BoolRing{
+		bits:     make([]uint64, (capacity+63)/64),
+		capacity: capacity,
+	}, nil
+}
+
+// Write inserts one value into the buffer, overwriting the oldest value (without error)
+// if the buffer is full
+func (br *BoolRing) Write(value bool) {
+	br.mut.Lock()
+	defer br.mut.Unlock()
+	br.write(value)
+}
+
+// WriteMany writes every value in order. An error is returned, and nothing is written,
+// if there are more values than the buffer capacity or if there are no values at all.
+func (br *BoolRing) WriteMany(values []bool) error {
+	br.mut.Lock()
+	defer br.mut.Unlock()
+
+	if len(values) > br.capacity {
+		return ErrCapacityTooSmall
+	} else if len(values) == 0 {
+		return ErrDataLengthIsZero
+	}
+	for _, value := range values {
+		br.write(value)
+	}
+	return nil
+}
+
+// write does the work for Write and must be called with br.mut held
+func (br *BoolRing) write(value bool) {
+	if br.elementCount == br.capacity {
+		if br.bit(br.writeIndex) {
+			br.trueCount--
+		}
+		br.overwrites++
+	} else {
+		br.elementCount++
+	}
+	br.setBit(br.writeIndex, value)
+	if value {
+		br.trueCount++
+	}
+	br.writeIndex = (br.writeIndex + 1) % br.capacity
+
+	// Extend the tail run or start a new one. The run can never be longer than the
+	// number of values in the buffer, since the oldest value may have just been evicted
+	if br.runLength > 0 && br.runValue == value {
+		br.runLength++
+	} else {
+		br.runValue = value
+		br.runLength = 1
+	}
+	if br.runLength > br.elementCount {
+		br.runLength = br.elementCount
+	}
+}
+
+// Read returns the contents of the buffer in "First-In First-Out" (FIFO) order
+func (br *BoolRing) Read() []bool {
+	br.mut.RLock()
+	defer br.mut.RUnlock()
+
+	result := make([]bool, br.elementCount)
+	for i := range result {
+		result[i] = br.bit((br.writeIndex + br.capacity - br.elementCount + i) % br.capacity)
+	}
+	return result
+}
+
+// CountTrue returns the number of true values within the buffer
+func (br *BoolRing) CountTrue() int {
+	br.mut.RLock()
+	defer br.mut.RUnlock()
+	return br.trueCount
+}
+
+// Ratio returns the fraction of values within the buffer that are true, between 0 and 1.
+// It returns 0 if the buffer is empty.
+func (br *BoolRing) Ratio() float64 {
+	br.mut.RLock()
+	defer br.mut.RUnlock()
+
+	if br.elementCount == 0 {
+		return 0
+	}
+	return float64(br.trueCount) / float64(br.elementCount)
+}
+
+// TailRun returns the newest value and the number of consecutive values equal to it at
+// the tail of the buffer. For example, a health check history ending in three failures
+// returns (false, 3). The length is zero if the buffer is empty.
+func (br *BoolRing) TailRun() (value bool, length int) {
+	br.mut.RLock()
+	defer br.mut.RUnlock()
+	return br.runValue, br.runLength
+}
+
+// Trailing returns the number of consecutive values equal to value at the tail of the
+// buffer, e.g. Trailing(false) is the number of failures since the last success.
+func (br *BoolRing) Trailing(value bool) int {
+	runValue, length := br.TailRun()
+	if runValue != value {
+		return 0
+	}
+	return length
+}
+
+// String converts the capacity, writeIndex pointer, count of elements, and contents of
+// the ring buffer into a string, then returns that string
+func (br *BoolRing) String() string {
+	br.mut.RLock()
+	defer br.mut.RUnlock()
+
+	bufferStr := "capacity=" + strconv.Itoa(br.capacity) +
+		", writeIndex=" + strconv.Itoa(br.writeIndex) +
+		", elementCount=" + strconv.Itoa(br.elementCount) +
+		", buffer=["
+	for i := 0; i < br.capacity; i++ {
+		if i != 0 {
+			bufferStr += ","
+		}
+		bufferStr += strconv.FormatBool(br.bit(i))
+	}
+	return bufferStr + "]"
+}
+
+// Reset deletes all data within the buffer by re-allocation but retains the same exact
+// capacity
+func (br *BoolRing) Reset() {
+	br.mut.Lock()
+	defer br.mut.Unlock()
+
+	br.bits = make([]uint64, len(br.bits))
+	br.elementCount = 0
+	br.writeIndex = 0
+	br.trueCount = 0
+	br.runValue = false
+	br.runLength = 0
+}
+
+// Overwrites returns the total number of values that were overwritten by Write because
+// the buffer was full. The count is retained across Reset().
+func (br *BoolRing) Overwrites() uint64 {
+	br.mut.RLock()
+	defer br.mut.RUnlock()
+	return br.overwrites
+}
+
+// Length returns the number of values within the buffer.
+//
+// For getting the total capacity of the buffer, use Capacity() or Size()
+func (br *BoolRing) Length() int {
+	br.mut.RLock()
+	defer br.mut.RUnlock()
+	return br.elementCount
+}
+
+// Capacity returns the zero-indexed capacity of the ring buffer itself, as opposed to the
+// number of values within the buffer.
+//
+// For getting the number of values in a buffer, use Length()
+func (br *BoolRing) Capacity() int {
+	br.mut.RLock()
+	defer br.mut.RUnlock()
+	return br.capacity
+}
+
+// Size works exactly the same as Capacity()
+func (br *BoolRing) Size() int {
+	return br.Capacity()
+}
+
+// IsFull returns a boolean indicating if the number of values of the buffer equals the
+// buffer capacity or size.
+func (br *BoolRing) IsFull() bool {
+	br.mut.RLock()
+	defer br.mut.RUnlock()
+	return br.elementCount == br.capacity
+}
+
+// IsEmpty returns a boolean indicating if the number of values within the buffer is
+// zero.
+func (br *BoolRing) IsEmpty() bool {
+	br.mut.RLock()
+	defer br.mut.RUnlock()
+	return br.elementCount == 0
+}
+
+// metrics takes a consistent view of the buffer for the metrics Registry. Booleans are
+// not numeric, so no window statistics are exported
+func (br *BoolRing) metrics() bufferMetrics {
+	br.mut.RLock()
+	defer br.mut.RUnlock()
+	return bufferMetrics{
+		length:     br.elementCount,
+		capacity:   br.capacity,
+		overwrites: br.overwrites,
+	}
+}
+
+// bit returns the value stored at index i of the bitset
+func (br *BoolRing) bit(i int) bool {
+	return br.bits[i/64]&(1<<(uint(i)%64)) != 0
+}
+
+// setBit stores value at index i of the bitset
+func (br *BoolRing) setBit(i int, value bool) {
+	if value {
+		br.bits[i/64] |= 1 << (uint(i) % 64)
+	} else {
+		br.bits[i/64] &^= 1 << (uint(i) % 64)
+	}
+}
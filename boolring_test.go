@@ -0,0 +1,96 @@
+package ringbuffer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewBoolRing(t *testing.T) {
+	if _, err := NewBoolRing(0); !errors.Is(err, ErrCapacityNegativeOrZero) {
+		t.Errorf("incorrect error, expected %v but got %v", ErrCapacityNegativeOrZero, err)
+		t.Fail()
+	}
+	br, err := NewBoolRing(130)
+	if err != nil || len(br.bits) != 3 {
+		t.Errorf("incorrect bitset size for capacity 130, expected %d words (%v)", 3, err)
+		t.Fail()
+	}
+}
+
+func TestBoolRing(t *testing.T) {
+	br, _ := NewBoolRing(70)
+
+	// Write 65 successes followed by 5 failures, then 3 more failures so that the
+	// oldest successes are overwritten across a 64-bit word boundary
+	for i := 0; i < 65; i++ {
+		br.Write(true)
+	}
+	if err := br.WriteMany([]bool{false, false, false, false, false}); err != nil {
+		t.Errorf("failed to write to buffer: %s", err)
+		t.Fail()
+	}
+	for i := 0; i < 3; i++ {
+		br.Write(false)
+	}
+
+	if br.Length() != 70 || !br.IsFull() {
+		t.Errorf("incorrect length, expected a full buffer of %d but got %d", 70, br.Length())
+		t.Fail()
+	}
+	if br.CountTrue() != 62 {
+		t.Errorf("incorrect result on CountTrue(), expected %d but got %d", 62, br.CountTrue())
+		t.Fail()
+	}
+	if ratio := br.Ratio(); ratio != 62.0/70 {
+		t.Errorf("incorrect result on Ratio(), expected %v but got %v", 62.0/70, ratio)
+		t.Fail()
+	}
+	if value, length := br.TailRun(); value || length != 8 {
+		t.Errorf("incorrect result on TailRun(), expected (false, 8) but got (%v, %d)", value, length)
+		t.Fail()
+	}
+	if br.Trailing(false) != 8 || br.Trailing(true) != 0 {
+		t.Errorf("incorrect result on Trailing(), expected 8 failures and 0 successes")
+		t.Fail()
+	}
+	if br.Overwrites() != 3 {
+		t.Errorf("incorrect overwrite count, expected %d but got %d", 3, br.Overwrites())
+		t.Fail()
+	}
+
+	read := br.Read()
+	if read[0] != true || read[61] != true || read[62] != false {
+		t.Errorf("incorrect result on Read(), the window should be 62 successes followed by 8 failures")
+		t.Fail()
+	}
+
+	br.Reset()
+	if !br.IsEmpty() || br.CountTrue() != 0 || br.Ratio() != 0 {
+		t.Errorf("buffer should be empty after Reset()")
+		t.Fail()
+	}
+}
+
+func TestBoolRingTailRun(t *testing.T) {
+	br, _ := NewBoolRing(3)
+	for i := 0; i < 5; i++ {
+		br.Write(false)
+	}
+	// The run can not be longer than the buffer itself
+	if value, length := br.TailRun(); value || length != 3 {
+		t.Errorf("incorrect result on TailRun(), expected (false, 3) but got (%v, %d)", value, length)
+		t.Fail()
+	}
+	br.Write(true)
+	expected := []bool{false, false, true}
+	if !reflect.DeepEqual(expected, br.Read()) {
+		t.Errorf("incorrect result on Read(), expected %v but got %v", expected, br.Read())
+		t.Fail()
+	}
+	if value, length := br.TailRun(); !value || length != 1 {
+		t.Errorf("incorrect result on TailRun(), expected (true, 1) but got (%v, %d)", value, length)
+		t.Fail()
+	}
+	t.Logf("buffer: %s", br.String())
+}
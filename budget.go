@@ -0,0 +1,148 @@
+package ringbuffer
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Error handling statements for byte-budgeted buffers
+var (
+	ErrElementTooLarge = errors.New("failed to write to buffer! The size of the " +
+		"value exceeds the byte budget of the buffer")
+)
+
+// Bytes returns the total size in bytes of all values within the buffer, as measured
+// by the size function of the buffer. It always returns zero unless the buffer was
+// created with WithByteBudget.
+func (rb *RingBuffer[T]) Bytes() int {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+	return rb.bytes
+}
+
+// ByteBudget returns the maximum total size in bytes of all values within the buffer,
+// or zero if the buffer was not created with WithByteBudget
+func (rb *RingBuffer[T]) ByteBudget() int {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+	return rb.budget
+}
+
+// reserve makes room within the byte budget for value by calling evict until it fits,
+// and returns the size of value. Nothing is evicted if the value can not be stored at
+// all. It must be called with rb.mut held
+func (rb *RingBuffer[T]) reserve(value T, evict func()) (int, error) {
+	if rb.budget == 0 {
+		return 0, nil
+	}
+	size := rb.sizeOf(value)
+	if size > rb.budget {
+		return 0, ErrElementTooLarge
+	}
+	if rb.bytes+size > rb.budget {
+		if rb.overflow == OverflowReject {
+			return 0, ErrBufferFull
+		}
+		for rb.bytes+size > rb.budget {
+			evict()
+		}
+	}
+	return size, nil
+}
+
+// release subtracts the size of a value that is removed from the buffer from the bytes
+// in use. It must be called with rb.mut held
+func (rb *RingBuffer[T]) release(value T) {
+	if rb.budget > 0 {
+		rb.bytes -= rb.sizeOf(value)
+	}
+}
+
+// replace stores value at index i of the backing array in place of an existing element.
+// If the buffer has a byte budget and the new value no longer fits, the elements older
+// than it are evicted until it does; the element itself and newer elements are never
+// evicted. Nothing changes if the value can not fit even then. It must be called with
+// rb.mut held
+func (rb *RingBuffer[T]) replace(i int, value T) error {
+	evictions := 0
+	if rb.budget > 0 {
+		size := rb.sizeOf(value)
+		if size > rb.budget {
+			return ErrElementTooLarge
+		}
+		bytes := rb.bytes - rb.sizeOf(rb.buffer[i]) + size
+		if bytes > rb.budget && rb.overflow == OverflowReject {
+			return ErrBufferFull
+		}
+		older := (i - rb.index(0) + rb.capacity) % rb.capacity
+		for ; bytes > rb.budget && evictions < older; evictions++ {
+			bytes -= rb.sizeOf(rb.buffer[rb.index(evictions)])
+		}
+		if bytes > rb.budget {
+			return ErrBufferFull
+		}
+	}
+
+	for ; evictions > 0; evictions-- {
+		rb.evictFront()
+	}
+	rb.release(rb.buffer[i])
+	if rb.budget > 0 {
+		rb.bytes += rb.sizeOf(value)
+	}
+	rb.detach()
+	rb.buffer[i] = value
+	rb.mutated()
+	return nil
+}
+
+// evictFront evicts the front (oldest) element to make room for new values. It must be
+// called with rb.mut held and a non-empty buffer
+func (rb *RingBuffer[T]) evictFront() {
+	front := rb.index(0)
+	rb.evict(front)
+	rb.rewind()
+}
+
+// evictBack evicts the back (newest) element to make room for new values at the front.
+// It must be called with rb.mut held and a non-empty buffer
+func (rb *RingBuffer[T]) evictBack() {
+	back := rb.backIndex()
+	rb.evict(back)
+	rb.writeIndex = back
+	rb.rewind()
+}
+
+// evict removes the element at index i of the backing array, which must be at either
+// end of the buffer, and counts it as overwritten
+func (rb *RingBuffer[T]) evict(i int) {
+	if rb.onEvict != nil {
+		rb.onEvict(rb.buffer[i])
+	}
+	rb.release(rb.buffer[i])
+	rb.clear(i)
+	rb.elementCount--
+	rb.overwrites++
+}
+
+// defaultSizeFunc returns the function measuring values of type T when no size function
+// is given: the length of strings, and the fixed size in memory of every other type
+func defaultSizeFunc[T BufferType]() func(T) int {
+	var zero T
+	size := 0
+	switch any(zero).(type) {
+	case string:
+		return func(value T) int { return len(any(value).(string)) }
+	case bool, byte:
+		size = 1
+	case int16, uint16:
+		size = 2
+	case int32, uint32, float32:
+		size = 4
+	case int64, uint64, float64:
+		size = 8
+	case int, uint:
+		size = strconv.IntSize / 8
+	}
+	return func(T) int { return size }
+}
@@ -0,0 +1,161 @@
+package ringbuffer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWithByteBudget(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected error
+	}{
+		{"zero budget", []Option{WithByteBudget(0)}, ErrByteBudgetNegativeOrZero},
+		{"nil size func", []Option{WithByteBudget(10), WithSizeFunc(nil)}, ErrNilSizeFunc},
+		{"size func type", []Option{WithByteBudget(10), WithSizeFunc(func(int) int { return 1 })}, ErrSizeFuncType},
+		{"valid", []Option{WithByteBudget(10), WithSizeFunc(func(string) int { return 1 })}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := New[string](3, test.opts...); !errors.Is(err, test.expected) {
+				t.Errorf("incorrect error, expected %v but got %v", test.expected, err)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestByteBudgetWrite(t *testing.T) {
+	var evicted []string
+	rb, _ := New[string](10, WithByteBudget(10),
+		WithEvictHook(func(value string) { evicted = append(evicted, value) }))
+	if err := rb.WriteMany([]string{"aaaa", "bbb", "cc"}); err != nil {
+		t.Errorf("failed to write to buffer: %s", err)
+		t.Fail()
+	}
+	if rb.Bytes() != 9 || rb.ByteBudget() != 10 {
+		t.Errorf("incorrect byte usage, expected %d of %d but got %d of %d", 9, 10, rb.Bytes(), rb.ByteBudget())
+		t.Fail()
+	}
+
+	// Writing 6 more bytes must evict the two oldest strings to fit within 10 bytes
+	rb.Write("dddddd")
+	if !reflect.DeepEqual([]string{"cc", "dddddd"}, rb.Read()) {
+		t.Errorf("incorrect result on Read(), expected %v but got %v", []string{"cc", "dddddd"}, rb.Read())
+		t.Fail()
+	}
+	if !reflect.DeepEqual([]string{"aaaa", "bbb"}, evicted) {
+		t.Errorf("incorrect evicted values, expected %v but got %v", []string{"aaaa", "bbb"}, evicted)
+		t.Fail()
+	}
+	if rb.Bytes() != 8 {
+		t.Errorf("incorrect byte usage, expected %d but got %d", 8, rb.Bytes())
+		t.Fail()
+	}
+
+	if err := rb.TryWrite("eeeeeeeeeee"); !errors.Is(err, ErrElementTooLarge) {
+		t.Errorf("incorrect error on TryWrite(), expected %v but got %v", ErrElementTooLarge, err)
+		t.Fail()
+	}
+	if err := rb.WriteMany([]string{"f", "eeeeeeeeeee"}); !errors.Is(err, ErrElementTooLarge) {
+		t.Errorf("incorrect error on WriteMany(), expected %v but got %v", ErrElementTooLarge, err)
+		t.Fail()
+	}
+
+	// Removing and replacing values must keep the usage up to date
+	if _, err := rb.PopFront(); err != nil {
+		t.Errorf("an error was not expected on PopFront(): %s", err)
+		t.Fail()
+	}
+	if err := rb.Set(0, "gg"); err != nil || rb.Bytes() != 2 {
+		t.Errorf("incorrect byte usage after Set(), expected %d but got %d (%v)", 2, rb.Bytes(), err)
+		t.Fail()
+	}
+	rb.RemoveIf(func(string) bool { return true })
+	if rb.Bytes() != 0 {
+		t.Errorf("incorrect byte usage after removing every value, expected %d but got %d", 0, rb.Bytes())
+		t.Fail()
+	}
+}
+
+func TestByteBudgetReject(t *testing.T) {
+	rb, _ := New[string](10, WithByteBudget(5), WithOverflow(OverflowReject))
+	rb.Write("abc")
+	if err := rb.TryWrite("def"); !errors.Is(err, ErrBufferFull) {
+		t.Errorf("incorrect error on TryWrite(), expected %v but got %v", ErrBufferFull, err)
+		t.Fail()
+	}
+	if err := rb.WriteMany([]string{"d", "ef"}); !errors.Is(err, ErrBufferFull) {
+		t.Errorf("incorrect error on WriteMany(), expected %v but got %v", ErrBufferFull, err)
+		t.Fail()
+	}
+	if err := rb.PushFront("xy"); err != nil {
+		t.Errorf("an error was not expected on PushFront(): %s", err)
+		t.Fail()
+	}
+	if !reflect.DeepEqual([]string{"xy", "abc"}, rb.Read()) || rb.Bytes() != 5 {
+		t.Errorf("incorrect result on Read(), expected %v using %d bytes but got %v using %d bytes",
+			[]string{"xy", "abc"}, 5, rb.Read(), rb.Bytes())
+		t.Fail()
+	}
+}
+
+func TestByteBudgetReplace(t *testing.T) {
+	tests := []struct {
+		name     string
+		index    int
+		value    string
+		expected []string
+		err      error
+	}{
+		{"oldest element", 0, "xxxx", []string{"aa", "bb", "cc"}, ErrBufferFull},
+		{"evicts older elements", 2, "xxxx", []string{"bb", "xxxx"}, nil},
+		{"evicts all older elements", 2, "xxxxx", []string{"xxxxx"}, nil},
+		{"evicts only what is needed", 1, "xxx", []string{"xxx", "cc"}, nil},
+		{"fits", 1, "x", []string{"aa", "x", "cc"}, nil},
+		{"too large", 1, "xxxxxxx", []string{"aa", "bb", "cc"}, ErrElementTooLarge},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rb, _ := New[string](3, WithByteBudget(6))
+			rb.WriteMany([]string{"aa", "bb", "cc"})
+			if err := rb.Set(test.index, test.value); !errors.Is(err, test.err) {
+				t.Errorf("incorrect error on Set(), expected %v but got %v", test.err, err)
+				t.Fail()
+			}
+			bytes := 0
+			for _, value := range test.expected {
+				bytes += len(value)
+			}
+			if !reflect.DeepEqual(test.expected, rb.Read()) || rb.Bytes() != bytes {
+				t.Errorf("incorrect result on Read(), expected %v using %d bytes but got %v using %d bytes",
+					test.expected, bytes, rb.Read(), rb.Bytes())
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestDefaultSizeFunc(t *testing.T) {
+	if size := defaultSizeFunc[string]()("hello"); size != 5 {
+		t.Errorf("incorrect string size, expected %d but got %d", 5, size)
+		t.Fail()
+	}
+	if size := defaultSizeFunc[float32]()(1.5); size != 4 {
+		t.Errorf("incorrect float32 size, expected %d but got %d", 4, size)
+		t.Fail()
+	}
+
+	rb, _ := New[int64](10, WithByteBudget(16))
+	for i := int64(0); i < 5; i++ {
+		rb.Write(i)
+	}
+	if !reflect.DeepEqual([]int64{3, 4}, rb.Read()) {
+		t.Errorf("incorrect result on Read(), expected %v but got %v", []int64{3, 4}, rb.Read())
+		t.Fail()
+	}
+}
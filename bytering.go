@@ -0,0 +1,169 @@
+package ringbuffer
+
+import (
+	"errors"
+	"sync"
+)
+
+// Error handling statements for ByteRing
+var (
+	ErrCommitTooLarge = errors.New("failed to commit to byte ring! More bytes were " +
+		"committed than reserved")
+	ErrConsumeTooLarge = errors.New("failed to consume from byte ring! More bytes " +
+		"were consumed than readable")
+)
+
+// ByteRing is a ring buffer of raw bytes with bip-buffer semantics: writers reserve a
+// contiguous region of the buffer and fill it in place, and readers are handed
+// contiguous regions of the buffered bytes. This lets a producer read from a socket
+// straight into the buffer without an intermediate copy:
+//
+//	region := br.Reserve(4096)
+//	n, err := conn.Read(region)
+//	br.Commit(n)
+//
+// Unlike RecordRing, a ByteRing stores a stream of bytes without record boundaries and
+// never overwrites unread data; Reserve returns nil when there is not enough contiguous
+// space.
+//
+// Internally the buffered bytes live in up to two regions, A and B. Region B starts at
+// the beginning of the buffer and only grows once there is no room left after region A,
+// so that every region handed out is contiguous.
+type ByteRing struct {
+	buf      []byte
+	mut      sync.Mutex
+	aStart   int  // Start of region A, the oldest buffered bytes
+	aEnd     int  // End of region A
+	bEnd     int  // End of region B, which always starts at offset 0
+	bInUse   bool // Set once region B holds committed bytes
+	resStart int  // Start of the outstanding reservation
+	resLen   int  // Length of the outstanding reservation; zero when there is none
+}
+
+// NewByteRing creates a new byte ring with a fixed capacity in bytes
+func NewByteRing(capacity int) (*ByteRing, error) {
+	if capacity <= 0 {
+		return nil, ErrCapacityNegativeOrZero
+	}
+	return &ByteRing{buf: make([]byte, capacity)}, nil
+}
+
+// Reserve returns a contiguous, writable region of exactly n bytes inside the buffer,
+// or nil if there is no such region. The bytes only become readable once they are
+// committed with Commit. Only one reservation can be outstanding at a time; calling
+// Reserve again replaces the previous reservation.
+func (br *ByteRing) Reserve(n int) []byte {
+	br.mut.Lock()
+	defer br.mut.Unlock()
+
+	br.resLen = 0
+	if n <= 0 {
+		return nil
+	}
+
+	switch {
+	case br.bInUse:
+		// Region B grows towards the start of region A
+		if br.aStart-br.bEnd < n {
+			return nil
+		}
+		br.resStart = br.bEnd
+	case len(br.buf)-br.aEnd >= n:
+		br.resStart = br.aEnd
+	case br.aStart >= n:
+		// There is no room left after region A, so start region B
+		br.resStart = 0
+	default:
+		return nil
+	}
+	br.resLen = n
+	return br.buf[br.resStart : br.resStart+n : br.resStart+n]
+}
+
+// Commit makes the first n bytes of the outstanding reservation readable and releases
+// the rest of it. An error is returned if n is larger than the reservation.
+func (br *ByteRing) Commit(n int) error {
+	br.mut.Lock()
+	defer br.mut.Unlock()
+
+	if n < 0 || n > br.resLen {
+		return ErrCommitTooLarge
+	}
+	start := br.resStart
+	br.resLen = 0
+	if n == 0 {
+		return nil
+	}
+
+	switch {
+	case br.aStart == br.aEnd && !br.bInUse:
+		// Region A is empty, so the reservation becomes region A
+		br.aStart = start
+		br.aEnd = start + n
+	case start == br.aEnd:
+		br.aEnd += n
+	default:
+		br.bEnd += n
+		br.bInUse = true
+	}
+	return nil
+}
+
+// ReadableRegion returns the oldest contiguous region of committed bytes, or an empty
+// slice if nothing is buffered. More bytes may be buffered after the region wraps
+// around; they are returned once the region has been consumed.
+func (br *ByteRing) ReadableRegion() []byte {
+	br.mut.Lock()
+	defer br.mut.Unlock()
+	return br.buf[br.aStart:br.aEnd:br.aEnd]
+}
+
+// Consume releases the first n bytes of the readable region so that their space can be
+// reserved again. An error is returned if n is larger than the readable region.
+func (br *ByteRing) Consume(n int) error {
+	br.mut.Lock()
+	defer br.mut.Unlock()
+
+	if n < 0 || n > br.aEnd-br.aStart {
+		return ErrConsumeTooLarge
+	}
+	br.aStart += n
+	if br.aStart == br.aEnd {
+		if br.bInUse {
+			// Region A is drained, so region B takes its place
+			br.aStart = 0
+			br.aEnd = br.bEnd
+			br.bEnd = 0
+			br.bInUse = false
+		} else {
+			// Start over at the beginning of the buffer to keep regions large
+			br.aStart = 0
+			br.aEnd = 0
+		}
+	}
+	return nil
+}
+
+// Buffered returns the number of committed bytes that have not been consumed yet
+func (br *ByteRing) Buffered() int {
+	br.mut.Lock()
+	defer br.mut.Unlock()
+	return br.aEnd - br.aStart + br.bEnd
+}
+
+// Capacity returns the total size of the buffer in bytes
+func (br *ByteRing) Capacity() int {
+	return len(br.buf)
+}
+
+// Reset discards all buffered bytes and any outstanding reservation
+func (br *ByteRing) Reset() {
+	br.mut.Lock()
+	defer br.mut.Unlock()
+
+	br.aStart = 0
+	br.aEnd = 0
+	br.bEnd = 0
+	br.bInUse = false
+	br.resLen = 0
+}
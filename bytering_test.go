@@ -0,0 +1,85 @@
+package ringbuffer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestByteRing(t *testing.T) {
+	br, _ := NewByteRing(10)
+
+	region := br.Reserve(8)
+	if len(region) != 8 {
+		t.Errorf("incorrect reservation, expected %d bytes but got %d", 8, len(region))
+		t.Fail()
+	}
+	copy(region, "abcdefgh")
+	if err := br.Commit(9); !errors.Is(err, ErrCommitTooLarge) {
+		t.Errorf("incorrect error on Commit(), expected %v but got %v", ErrCommitTooLarge, err)
+		t.Fail()
+	}
+	br.Reserve(8)
+	if err := br.Commit(8); err != nil {
+		t.Errorf("an error was not expected on Commit(): %s", err)
+		t.Fail()
+	}
+
+	// Only 2 bytes are left after region A, so a 3 byte reservation fails until some
+	// bytes are consumed and region B can start at the beginning of the buffer
+	if region := br.Reserve(3); region != nil {
+		t.Errorf("reservation should fail without contiguous space but got %d bytes", len(region))
+		t.Fail()
+	}
+	if err := br.Consume(5); err != nil {
+		t.Errorf("an error was not expected on Consume(): %s", err)
+		t.Fail()
+	}
+	copy(br.Reserve(3), "xyz")
+	_ = br.Commit(3)
+	if region := br.Reserve(3); region != nil {
+		t.Errorf("region B must not grow into region A but got %d bytes", len(region))
+		t.Fail()
+	}
+	copy(br.Reserve(2), "12")
+	_ = br.Commit(2)
+
+	if br.Buffered() != 8 {
+		t.Errorf("incorrect buffered count, expected %d but got %d", 8, br.Buffered())
+		t.Fail()
+	}
+	if string(br.ReadableRegion()) != "fgh" {
+		t.Errorf("incorrect readable region, expected %q but got %q", "fgh", br.ReadableRegion())
+		t.Fail()
+	}
+	if err := br.Consume(4); !errors.Is(err, ErrConsumeTooLarge) {
+		t.Errorf("incorrect error on Consume(), expected %v but got %v", ErrConsumeTooLarge, err)
+		t.Fail()
+	}
+	_ = br.Consume(3)
+	if string(br.ReadableRegion()) != "xyz12" {
+		t.Errorf("incorrect readable region after wrapping, expected %q but got %q", "xyz12", br.ReadableRegion())
+		t.Fail()
+	}
+
+	br.Reset()
+	if br.Buffered() != 0 || len(br.Reserve(10)) != 10 {
+		t.Errorf("the whole buffer should be available after Reset()")
+		t.Fail()
+	}
+}
+
+func TestByteRingEmptyRegionA(t *testing.T) {
+	br, _ := NewByteRing(4)
+	copy(br.Reserve(2), "ab")
+	_ = br.Commit(2)
+
+	// Once region A is drained the buffer starts over, so the whole capacity can be
+	// reserved again
+	_ = br.Consume(2)
+	copy(br.Reserve(4), "wxyz")
+	_ = br.Commit(4)
+	if string(br.ReadableRegion()) != "wxyz" {
+		t.Errorf("incorrect readable region, expected %q but got %q", "wxyz", br.ReadableRegion())
+		t.Fail()
+	}
+}
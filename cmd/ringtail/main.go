@@ -0,0 +1,253 @@
+// Command ringtail keeps the last lines of its input in memory and prints them when it
+// is asked to, e.g. to capture the context of a crash in a container without writing
+// every line to disk.
+//
+// Usage:
+//
+//	ringtail [flags] [file]
+//
+// ringtail reads standard input, or file if one is given. The retained lines are
+// printed when the input ends, when ringtail is interrupted or terminated, and every
+// time it receives SIGUSR1 (on Unix systems). With -f, ringtail keeps following the
+// file as it grows, like tail -f, until it is stopped.
+//
+// By default ringtail keeps the last -n lines. With -d, it keeps the lines received
+// within that duration instead, and -n only caps how many of them are held in memory.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/euheimr/ringbuffer"
+)
+
+// Default number of lines to keep, without and with -d
+const (
+	defaultLines       = 200
+	defaultWindowLines = 100000
+)
+
+// pruneInterval is how often lines older than the -d window are dropped from memory
+const pruneInterval = time.Second
+
+// config holds the parsed command line
+type config struct {
+	lines      int
+	maxLineLen int
+	window     time.Duration
+	grep       *regexp.Regexp
+	json       bool
+	follow     bool
+	poll       time.Duration
+	path       string
+}
+
+func main() {
+	cfg, err := parseFlags(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	} else if err != nil {
+		os.Exit(2)
+	}
+
+	in := io.Reader(os.Stdin)
+	if cfg.path != "" {
+		f, err := os.Open(cfg.path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "ringtail:", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+		if cfg.follow {
+			in = &follower{file: f, poll: cfg.poll}
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	dump := make(chan os.Signal, 1)
+	if len(dumpSignals) > 0 {
+		signal.Notify(dump, dumpSignals...)
+	}
+
+	if err := run(ctx, cfg, in, os.Stdout, dump); err != nil {
+		fmt.Fprintln(os.Stderr, "ringtail:", err)
+		os.Exit(1)
+	}
+}
+
+// parseFlags parses the command line arguments, printing usage and errors to stderr
+func parseFlags(args []string, stderr io.Writer) (*config, error) {
+	cfg := &config{}
+	fs := flag.NewFlagSet("ringtail", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: ringtail [flags] [file]")
+		fs.PrintDefaults()
+	}
+
+	var grep string
+	fs.IntVar(&cfg.lines, "n", defaultLines, "number of `lines` to keep, or with -d the maximum number kept in\n"+
+		"memory ("+strconv.Itoa(defaultWindowLines)+" with -d unless set)")
+	fs.IntVar(&cfg.maxLineLen, "max-line", 4096, "maximum line length in `bytes`; longer lines are truncated")
+	fs.DurationVar(&cfg.window, "d", 0, "keep the lines received within this `duration` instead of the last -n lines")
+	fs.StringVar(&grep, "grep", "", "only keep lines matching this `regexp`")
+	fs.BoolVar(&cfg.json, "json", false, "print lines as JSON objects with their receive time")
+	fs.BoolVar(&cfg.follow, "f", false, "keep following file as it grows")
+	fs.DurationVar(&cfg.poll, "poll", 250*time.Millisecond, "how often to check a followed file for new data")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case fs.NArg() > 1:
+		return nil, usageError(fs, "at most one file may be given")
+	case cfg.follow && fs.NArg() == 0:
+		return nil, usageError(fs, "-f requires a file")
+	case cfg.window < 0:
+		return nil, usageError(fs, "-d must not be negative")
+	case cfg.poll <= 0:
+		return nil, usageError(fs, "-poll must be greater than zero")
+	}
+	cfg.path = fs.Arg(0)
+
+	if cfg.window > 0 {
+		explicit := false
+		fs.Visit(func(f *flag.Flag) { explicit = explicit || f.Name == "n" })
+		if !explicit {
+			cfg.lines = defaultWindowLines
+		}
+	}
+
+	if grep != "" {
+		re, err := regexp.Compile(grep)
+		if err != nil {
+			return nil, usageError(fs, "invalid -grep: "+err.Error())
+		}
+		cfg.grep = re
+	}
+	return cfg, nil
+}
+
+// usageError prints msg and the usage to the output of fs, and returns msg as an error
+func usageError(fs *flag.FlagSet, msg string) error {
+	fmt.Fprintln(fs.Output(), "ringtail:", msg)
+	fs.Usage()
+	return errors.New(msg)
+}
+
+// run copies in into a line ring until in ends or ctx is canceled, dumping the ring to
+// out every time a value is received from dump and once more before returning
+func run(ctx context.Context, cfg *config, in io.Reader, out io.Writer, dump <-chan os.Signal) error {
+	lr, err := ringbuffer.NewLineRing(cfg.lines, cfg.maxLineLen, ringbuffer.WithClock(time.Now))
+	if err != nil {
+		return err
+	}
+	if cfg.grep != nil {
+		lr.SetFilter(cfg.grep.MatchString)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(lr, in)
+		done <- err
+	}()
+
+	var prune <-chan time.Time
+	if cfg.window > 0 {
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+		prune = ticker.C
+	}
+
+	for {
+		select {
+		case <-prune:
+			lr.DropBefore(time.Now().Add(-cfg.window))
+		case <-dump:
+			if err := writeDump(out, lr, cfg); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			lr.Flush()
+			return writeDump(out, lr, cfg)
+		case err := <-done:
+			lr.Flush()
+			if dumpErr := writeDump(out, lr, cfg); err == nil {
+				err = dumpErr
+			}
+			return err
+		}
+	}
+}
+
+// jsonLine is a line printed with -json
+type jsonLine struct {
+	Time time.Time `json:"time"`
+	Line string    `json:"line"`
+}
+
+// writeDump prints the lines kept in lr, first dropping lines older than the -d window
+func writeDump(out io.Writer, lr *ringbuffer.LineRing, cfg *config) error {
+	if cfg.window > 0 {
+		lr.DropBefore(time.Now().Add(-cfg.window))
+	}
+	snap := lr.Snapshot()
+	stamps := snap.Timestamps()
+
+	enc := json.NewEncoder(out)
+	var err error
+	snap.Range(func(i int, line string) bool {
+		if cfg.json {
+			err = enc.Encode(jsonLine{Time: stamps[i], Line: line})
+		} else {
+			_, err = io.WriteString(out, line+"\n")
+		}
+		return err == nil
+	})
+	return err
+}
+
+// follower reads a file like tail -f: at the end of the file it waits for more data
+// instead of returning io.EOF, and it starts over if the file is truncated
+type follower struct {
+	file   *os.File
+	poll   time.Duration
+	offset int64
+}
+
+// Read implements io.Reader and never returns io.EOF
+func (f *follower) Read(p []byte) (int, error) {
+	for {
+		n, err := f.file.Read(p)
+		f.offset += int64(n)
+		if n > 0 || (err != nil && err != io.EOF) {
+			return n, err
+		}
+
+		info, err := f.file.Stat()
+		if err != nil {
+			return 0, err
+		}
+		if info.Size() < f.offset {
+			if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+				return 0, err
+			}
+			f.offset = 0
+			continue
+		}
+		time.Sleep(f.poll)
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseFlagsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"two files", []string{"a", "b"}},
+		{"follow stdin", []string{"-f"}},
+		{"negative window", []string{"-d", "-1s"}},
+		{"zero poll", []string{"-poll", "0"}},
+		{"invalid regexp", []string{"-grep", "("}},
+		{"unknown flag", []string{"-x"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := parseFlags(test.args, io.Discard); err == nil {
+				t.Errorf("an error was expected for %q", test.args)
+				t.Fail()
+			}
+		})
+	}
+}
+
+// runArgs parses args and runs ringtail on input until it ends
+func runArgs(t *testing.T, input string, args ...string) string {
+	cfg, err := parseFlags(args, io.Discard)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %s", args, err)
+	}
+	var out bytes.Buffer
+	if err := run(context.Background(), cfg, strings.NewReader(input), &out, nil); err != nil {
+		t.Fatalf("failed to run: %s", err)
+	}
+	return out.String()
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		expected string
+	}{
+		{"last lines", []string{"-n", "2"}, "a\nb\nc", "b\nc\n"},
+		{"grep", []string{"-grep", "^ERR"}, "ERR 1\nOK\nERR 2\n", "ERR 1\nERR 2\n"},
+		{"max line", []string{"-max-line", "3"}, "abcdef\n", "abc\n"},
+		{"window", []string{"-d", "1h"}, "recent\n", "recent\n"},
+		// With -d, lines are kept by age rather than by the default line count
+		{"window beyond default lines", []string{"-d", "1h"}, strings.Repeat("line\n", 2*defaultLines),
+			strings.Repeat("line\n", 2*defaultLines)},
+		{"window capped", []string{"-d", "1h", "-n", "2"}, "a\nb\nc\n", "b\nc\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if out := runArgs(t, test.input, test.args...); out != test.expected {
+				t.Errorf("incorrect output, expected %q but got %q", test.expected, out)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	before := time.Now()
+	out := runArgs(t, "one\ntwo\n", "-json")
+
+	dec := json.NewDecoder(strings.NewReader(out))
+	for _, expected := range []string{"one", "two"} {
+		var line jsonLine
+		if err := dec.Decode(&line); err != nil {
+			t.Fatalf("failed to decode output %q: %s", out, err)
+		}
+		if line.Line != expected || line.Time.Before(before) {
+			t.Errorf("incorrect line, expected %q after %v but got %+v", expected, before, line)
+			t.Fail()
+		}
+	}
+}
+
+// syncBuffer is a bytes.Buffer that may be read while run writes to it
+type syncBuffer struct {
+	mut sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return b.buf.String()
+}
+
+func TestRunDump(t *testing.T) {
+	cfg, _ := parseFlags(nil, io.Discard)
+	pr, pw := io.Pipe()
+	var out syncBuffer
+	dump := make(chan os.Signal) // Unbuffered, so no dump is left pending below
+	done := make(chan error, 1)
+	go func() { done <- run(context.Background(), cfg, pr, &out, dump) }()
+
+	// The line may not have reached the ring yet, so dump until it shows up
+	io.WriteString(pw, "first\n")
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.HasSuffix(out.String(), "first\n") {
+		if time.Now().After(deadline) {
+			t.Fatalf("the line was not dumped in time, got %q", out.String())
+		}
+		select {
+		case dump <- os.Interrupt:
+		default:
+		}
+		time.Sleep(time.Millisecond)
+	}
+	dumped := out.String()
+
+	// The end of the input dumps once more, including a partial line
+	io.WriteString(pw, "partial")
+	pw.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("failed to run: %s", err)
+	}
+	expected := dumped + "first\npartial\n"
+	if out.String() != expected {
+		t.Errorf("incorrect output, expected %q but got %q", expected, out.String())
+		t.Fail()
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	cfg, _ := parseFlags(nil, io.Discard)
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	var out syncBuffer
+	done := make(chan error, 1)
+	go func() { done <- run(ctx, cfg, pr, &out, nil) }()
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("incorrect error after canceling, expected nil but got %v", err)
+		t.Fail()
+	}
+}
+
+func TestFollower(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	os.WriteFile(path, []byte("old\n"), 0o644)
+	f, _ := os.Open(path)
+	defer f.Close()
+	r := &follower{file: f, poll: time.Millisecond}
+
+	buf := make([]byte, 16)
+	n, _ := r.Read(buf)
+	if string(buf[:n]) != "old\n" {
+		t.Errorf("incorrect read, expected %q but got %q", "old\n", buf[:n])
+		t.Fail()
+	}
+
+	// Truncating and rewriting the file starts over from its beginning
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		os.WriteFile(path, []byte("new"), 0o644)
+	}()
+	n, err := r.Read(buf)
+	if err != nil || string(buf[:n]) != "new" {
+		t.Errorf("incorrect read after truncation, expected %q but got %q (%v)", "new", buf[:n], err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// dumpSignals are the signals that make ringtail print the kept lines. There is no
+// SIGUSR1 outside of Unix systems, so the lines are only printed when ringtail stops
+var dumpSignals []os.Signal
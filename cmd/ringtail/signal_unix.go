@@ -0,0 +1,11 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// dumpSignals are the signals that make ringtail print the kept lines
+var dumpSignals = []os.Signal{syscall.SIGUSR1}
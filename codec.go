@@ -0,0 +1,95 @@
+package ringbuffer
+
+import "strconv"
+
+// appendValue appends the text form of value to dst. Floats are formatted with the
+// fewest digits that parse back to the exact same value
+func appendValue[T BufferType](dst []byte, value T) []byte {
+	switch v := any(value).(type) {
+	case int:
+		return strconv.AppendInt(dst, int64(v), 10)
+	case int16:
+		return strconv.AppendInt(dst, int64(v), 10)
+	case int32:
+		return strconv.AppendInt(dst, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(dst, v, 10)
+	case byte:
+		return strconv.AppendUint(dst, uint64(v), 10)
+	case uint:
+		return strconv.AppendUint(dst, uint64(v), 10)
+	case uint16:
+		return strconv.AppendUint(dst, uint64(v), 10)
+	case uint32:
+		return strconv.AppendUint(dst, uint64(v), 10)
+	case uint64:
+		return strconv.AppendUint(dst, v, 10)
+	case float32:
+		return strconv.AppendFloat(dst, float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.AppendFloat(dst, v, 'g', -1, 64)
+	case bool:
+		return strconv.AppendBool(dst, v)
+	case string:
+		return append(dst, v...)
+	default:
+		return dst
+	}
+}
+
+// parseValue parses the text form of a value as written by appendValue. An error is
+// returned if s is not a valid value of type T, e.g. if it is out of range
+func parseValue[T BufferType](s string) (T, error) {
+	var zero T
+	var value any
+	var err error
+
+	switch any(zero).(type) {
+	case int:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, strconv.IntSize)
+		value = int(n)
+	case int16:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, 16)
+		value = int16(n)
+	case int32:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, 32)
+		value = int32(n)
+	case int64:
+		value, err = strconv.ParseInt(s, 10, 64)
+	case byte:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, 8)
+		value = byte(n)
+	case uint:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, strconv.IntSize)
+		value = uint(n)
+	case uint16:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, 16)
+		value = uint16(n)
+	case uint32:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, 32)
+		value = uint32(n)
+	case uint64:
+		value, err = strconv.ParseUint(s, 10, 64)
+	case float32:
+		var f float64
+		f, err = strconv.ParseFloat(s, 32)
+		value = float32(f)
+	case float64:
+		value, err = strconv.ParseFloat(s, 64)
+	case bool:
+		value, err = strconv.ParseBool(s)
+	case string:
+		value = s
+	}
+	if err != nil {
+		return zero, err
+	}
+	return value.(T), nil
+}
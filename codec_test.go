@@ -0,0 +1,48 @@
+package ringbuffer
+
+import (
+	"math"
+	"testing"
+)
+
+// roundTrip formats and parses value, failing the test if it does not come back intact
+func roundTrip[T BufferType](t *testing.T, value T, text string) {
+	formatted := string(appendValue(nil, value))
+	if formatted != text {
+		t.Errorf("incorrect text for %v, expected %q but got %q", value, text, formatted)
+		t.Fail()
+	}
+	parsed, err := parseValue[T](formatted)
+	if err != nil || parsed != value {
+		t.Errorf("incorrect parsed value for %q, expected %v but got %v (%v)", formatted, value, parsed, err)
+		t.Fail()
+	}
+}
+
+func TestCodec(t *testing.T) {
+	roundTrip(t, int(-42), "-42")
+	roundTrip(t, int16(math.MinInt16), "-32768")
+	roundTrip(t, int32(7), "7")
+	roundTrip(t, int64(math.MaxInt64), "9223372036854775807")
+	roundTrip(t, byte(255), "255")
+	roundTrip(t, uint(3), "3")
+	roundTrip(t, uint16(65535), "65535")
+	roundTrip(t, uint32(1), "1")
+	roundTrip(t, uint64(math.MaxUint64), "18446744073709551615")
+	roundTrip(t, float32(0.1), "0.1")
+	roundTrip(t, 0.1, "0.1")
+	roundTrip(t, math.Inf(-1), "-Inf")
+	roundTrip(t, true, "true")
+	roundTrip(t, "a,b\n", "a,b\n")
+}
+
+func TestParseValueErrors(t *testing.T) {
+	if _, err := parseValue[byte]("256"); err == nil {
+		t.Errorf("an error was expected when parsing an out of range byte")
+		t.Fail()
+	}
+	if _, err := parseValue[bool]("maybe"); err == nil {
+		t.Errorf("an error was expected when parsing an invalid bool")
+		t.Fail()
+	}
+}
@@ -0,0 +1,179 @@
+package ringbuffer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Error handling statements for CSV import and export
+var (
+	ErrCSVNoClock = errors.New("failed to write CSV! The timestamp column requires a " +
+		"buffer created with WithClock")
+	ErrCSVHeader = errors.New("failed to read CSV! The header row must have a value " +
+		"column and may only have index and timestamp columns besides it")
+	ErrCSVRow = errors.New("failed to read CSV! A row holds an invalid value or " +
+		"timestamp")
+)
+
+// Names of the columns in the header row of a CSV file
+const (
+	csvIndex     = "index"
+	csvTimestamp = "timestamp"
+	csvValue     = "value"
+)
+
+// CSVOption configures the columns written by WriteCSV
+type CSVOption func(*csvOptions)
+
+// csvOptions collects the settings of every CSVOption passed to WriteCSV
+type csvOptions struct {
+	index     bool
+	timestamp bool
+}
+
+// WithIndexColumn adds an index column to the CSV output, holding the logical index of
+// every element, where 0 is the oldest element
+func WithIndexColumn() CSVOption {
+	return func(o *csvOptions) {
+		o.index = true
+	}
+}
+
+// WithTimestampColumn adds a timestamp column to the CSV output, holding the time every
+// element was written in RFC 3339 format with nanoseconds. The buffer must have been
+// created with WithClock.
+func WithTimestampColumn() CSVOption {
+	return func(o *csvOptions) {
+		o.timestamp = true
+	}
+}
+
+// WriteCSV writes the contents of the buffer to w as CSV in "First-In First-Out" (FIFO)
+// order, one value per row after a header row. By default only the value column is
+// written; WithIndexColumn and WithTimestampColumn add an index column and a timestamp
+// column in front of it.
+//
+// Floats are written with the fewest digits that read back to the exact same value, and
+// bools as true or false.
+func (rb *RingBuffer[T]) WriteCSV(w io.Writer, opts ...CSVOption) error {
+	var o csvOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := rb.Snapshot()
+	if o.timestamp && s.stamps == nil {
+		return ErrCSVNoClock
+	}
+	stamps := s.Timestamps()
+
+	var header []string
+	if o.index {
+		header = append(header, csvIndex)
+	}
+	if o.timestamp {
+		header = append(header, csvTimestamp)
+	}
+	header = append(header, csvValue)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	row := make([]string, 0, len(header))
+	var err error
+	s.Range(func(i int, value T) bool {
+		row = row[:0]
+		if o.index {
+			row = append(row, strconv.Itoa(i))
+		}
+		if o.timestamp {
+			row = append(row, stamps[i].Format(time.RFC3339Nano))
+		}
+		row = append(row, string(appendValue(nil, value)))
+		if len(row) == 1 && row[0] == "" {
+			// csv.Writer writes a lone empty field as a blank line, which csv.Reader
+			// skips, so it is quoted by hand
+			cw.Flush()
+			if err = cw.Error(); err == nil {
+				_, err = io.WriteString(w, "\"\"\n")
+			}
+			return err == nil
+		}
+		err = cw.Write(row)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV replaces the contents of the buffer with the rows of a CSV file in the format
+// written by WriteCSV. The header row must have a value column; an index column is
+// ignored, and a timestamp column is used to restore the write times if the buffer was
+// created with WithClock. Values are parsed as the buffer type, e.g. "true" or "1" for
+// a bool.
+//
+// ErrCapacityTooSmall is returned if the file has more rows than the capacity of the
+// buffer, and the buffer is left unchanged if any error is returned. Like LoadFrom, the
+// oldest rows that do not fit within the byte budget of the buffer are dropped.
+func (rb *RingBuffer[T]) ReadCSV(r io.Reader) error {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return ErrCSVHeader
+	} else if err != nil {
+		return err
+	}
+
+	valueColumn, stampColumn := -1, -1
+	for i, name := range header {
+		switch name {
+		case csvValue:
+			valueColumn = i
+		case csvTimestamp:
+			stampColumn = i
+		case csvIndex:
+		default:
+			return ErrCSVHeader
+		}
+	}
+	if valueColumn < 0 {
+		return ErrCSVHeader
+	}
+
+	capacity := rb.Capacity()
+	var values []T
+	var stamps []time.Time
+	for n := 1; ; n++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if len(values) == capacity {
+			return ErrCapacityTooSmall
+		}
+
+		value, err := parseValue[T](row[valueColumn])
+		if err != nil {
+			return fmt.Errorf("%w (row %d: %s)", ErrCSVRow, n, err)
+		}
+		values = append(values, value)
+		if stampColumn >= 0 {
+			stamp, err := time.Parse(time.RFC3339Nano, row[stampColumn])
+			if err != nil {
+				return fmt.Errorf("%w (row %d: %s)", ErrCSVRow, n, err)
+			}
+			stamps = append(stamps, stamp)
+		}
+	}
+	return rb.replaceAll(values, stamps)
+}
@@ -0,0 +1,131 @@
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteCSV(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	clock := func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	rb, _ := New[float64](2, WithClock(clock))
+	for _, v := range []float64{0.1, 1e21, -2} {
+		rb.Write(v)
+	}
+
+	tests := []struct {
+		name     string
+		opts     []CSVOption
+		expected string
+	}{
+		{"values", nil, "value\n1e+21\n-2\n"},
+		{"index", []CSVOption{WithIndexColumn()}, "index,value\n0,1e+21\n1,-2\n"},
+		{"all columns", []CSVOption{WithIndexColumn(), WithTimestampColumn()},
+			"index,timestamp,value\n" +
+				"0,2024-01-01T00:00:02Z,1e+21\n" +
+				"1,2024-01-01T00:00:03Z,-2\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := rb.WriteCSV(&out, test.opts...); err != nil {
+				t.Fatalf("failed to write CSV: %s", err)
+			}
+			if out.String() != test.expected {
+				t.Errorf("incorrect CSV, expected %q but got %q", test.expected, out.String())
+				t.Fail()
+			}
+		})
+	}
+
+	noClock, _ := New[int](2)
+	if err := noClock.WriteCSV(&bytes.Buffer{}, WithTimestampColumn()); !errors.Is(err, ErrCSVNoClock) {
+		t.Errorf("incorrect error, expected %v but got %v", ErrCSVNoClock, err)
+		t.Fail()
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	rb, _ := New[string](4)
+	rb.WriteMany([]string{"a,b", "", "line\nbreak", `"quoted"`})
+
+	var out bytes.Buffer
+	if err := rb.WriteCSV(&out); err != nil {
+		t.Fatalf("failed to write CSV: %s", err)
+	}
+	loaded, _ := New[string](4)
+	if err := loaded.ReadCSV(&out); err != nil {
+		t.Fatalf("failed to read CSV: %s", err)
+	}
+	if !reflect.DeepEqual(loaded.Read(), rb.Read()) {
+		t.Errorf("incorrect contents, expected %q but got %q", rb.Read(), loaded.Read())
+		t.Fail()
+	}
+
+	bools, _ := New[bool](3)
+	if err := bools.ReadCSV(strings.NewReader("index,value\n0,true\n1,0\n2,F\n")); err != nil {
+		t.Fatalf("failed to read CSV: %s", err)
+	}
+	expected := []bool{true, false, false}
+	if !reflect.DeepEqual(bools.Read(), expected) {
+		t.Errorf("incorrect contents, expected %v but got %v", expected, bools.Read())
+		t.Fail()
+	}
+}
+
+func TestReadCSVTimestamps(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rb, _ := New[int](2, WithClock(func() time.Time { return now }))
+	csv := "timestamp,value\n2023-06-01T12:00:00.5Z,1\n2023-06-01T12:00:01Z,2\n"
+	if err := rb.ReadCSV(strings.NewReader(csv)); err != nil {
+		t.Fatalf("failed to read CSV: %s", err)
+	}
+
+	expected := []time.Time{
+		time.Date(2023, 6, 1, 12, 0, 0, 5e8, time.UTC),
+		time.Date(2023, 6, 1, 12, 0, 1, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(rb.Timestamps(), expected) {
+		t.Errorf("incorrect timestamps, expected %v but got %v", expected, rb.Timestamps())
+		t.Fail()
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		csv      string
+		expected error
+	}{
+		{"empty", "", ErrCSVHeader},
+		{"no value column", "index\n0\n", ErrCSVHeader},
+		{"unknown column", "value,extra\n1,2\n", ErrCSVHeader},
+		{"invalid value", "value\n1\n300\n", ErrCSVRow},
+		{"invalid timestamp", "timestamp,value\nyesterday,1\n", ErrCSVRow},
+		{"too many rows", "value\n1\n2\n3\n4\n", ErrCapacityTooSmall},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rb, _ := New[byte](3)
+			rb.Write(42)
+			if err := rb.ReadCSV(strings.NewReader(test.csv)); !errors.Is(err, test.expected) {
+				t.Errorf("incorrect error, expected %v but got %v", test.expected, err)
+				t.Fail()
+			}
+			if !reflect.DeepEqual(rb.Read(), []byte{42}) {
+				t.Errorf("the buffer changed after a failed ReadCSV(), got %v", rb.Read())
+				t.Fail()
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package ringbuffer
+
+import "time"
+
+// The methods in this file let a RingBuffer be used as a bounded double-ended queue
+// (deque). They operate on the same backing array as Write and Read: the front of the
+// deque is the oldest element and the back is the newest element.
+//
+// When a value is pushed into a full buffer, the overflow policy decides what happens.
+// With OverflowOverwrite (the default) the element at the opposite end is evicted, and
+// with OverflowReject the push fails with ErrBufferFull. The same applies when the
+// value does not fit within the byte budget of the buffer.
+
+// PushBack inserts one element at the back of the buffer. It behaves exactly like
+// TryWrite, evicting the front element if the buffer is full.
+func (rb *RingBuffer[T]) PushBack(value T) error {
+	return rb.TryWrite(value)
+}
+
+// PushFront inserts one element at the front of the buffer, so that it becomes the
+// oldest element. If the buffer is full, the back element is evicted.
+func (rb *RingBuffer[T]) PushFront(value T) error {
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+
+	if rb.elementCount == rb.capacity && rb.overflow == OverflowReject {
+		return ErrBufferFull
+	}
+	size, err := rb.reserve(value, rb.evictBack)
+	if err != nil {
+		return err
+	}
+	if rb.elementCount == rb.capacity {
+		rb.evictBack()
+	}
+
+	rb.detach()
+	front := (rb.index(0) + rb.capacity - 1) % rb.capacity
+	rb.buffer[front] = value
+	if rb.stamps != nil {
+		rb.stamps[front] = rb.now()
+	}
+	rb.bytes += size
+	rb.elementCount++
+	rb.mutated()
+	return nil
+}
+
+// PopFront removes and returns the front (oldest) element of the buffer. An error is
+// returned if the buffer is empty.
+func (rb *RingBuffer[T]) PopFront() (T, error) {
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+
+	if rb.elementCount == 0 {
+		var zero T
+		return zero, ErrBufferEmpty
+	}
+	front := rb.index(0)
+	value := rb.buffer[front]
+	rb.release(value)
+	rb.clear(front)
+	rb.elementCount--
+	rb.rewind()
+	rb.mutated()
+	return value, nil
+}
+
+// PopBack removes and returns the back (newest) element of the buffer. An error is
+// returned if the buffer is empty.
+func (rb *RingBuffer[T]) PopBack() (T, error) {
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+
+	if rb.elementCount == 0 {
+		var zero T
+		return zero, ErrBufferEmpty
+	}
+	back := rb.backIndex()
+	value := rb.buffer[back]
+	rb.release(value)
+	rb.clear(back)
+	rb.writeIndex = back
+	rb.elementCount--
+	rb.rewind()
+	rb.mutated()
+	return value, nil
+}
+
+// PeekFront returns the front (oldest) element of the buffer without removing it. An
+// error is returned if the buffer is empty.
+func (rb *RingBuffer[T]) PeekFront() (T, error) {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+
+	if rb.elementCount == 0 {
+		var zero T
+		return zero, ErrBufferEmpty
+	}
+	return rb.buffer[rb.index(0)], nil
+}
+
+// PeekBack returns the back (newest) element of the buffer without removing it. An
+// error is returned if the buffer is empty.
+func (rb *RingBuffer[T]) PeekBack() (T, error) {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+
+	if rb.elementCount == 0 {
+		var zero T
+		return zero, ErrBufferEmpty
+	}
+	return rb.buffer[rb.backIndex()], nil
+}
+
+// backIndex returns the index of the back (newest) element in the backing array
+func (rb *RingBuffer[T]) backIndex() int {
+	return (rb.writeIndex + rb.capacity - 1) % rb.capacity
+}
+
+// clear resets the slot at index i of the backing array to the default value of its
+// type, so removed values are not kept alive by the buffer
+func (rb *RingBuffer[T]) clear(i int) {
+	var zero T
+	rb.detach()
+	rb.buffer[i] = zero
+	if rb.stamps != nil {
+		rb.stamps[i] = time.Time{}
+	}
+}
+
+// rewind moves the logical write pointer back to the beginning of the buffer once the
+// buffer has been emptied, so that IsEmpty() holds again
+func (rb *RingBuffer[T]) rewind() {
+	if rb.elementCount == 0 {
+		rb.writeIndex = 0
+	}
+}
@@ -0,0 +1,86 @@
+package ringbuffer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPushFront(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   OverflowPolicy
+		expected []int
+		err      error
+	}{
+		{"evict back", OverflowOverwrite, []int{0, 1, 2}, nil},
+		{"reject", OverflowReject, []int{1, 2, 3}, ErrBufferFull},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var evicted []int
+			rb, _ := New[int](3, WithOverflow(test.policy),
+				WithEvictHook(func(value int) { evicted = append(evicted, value) }))
+			if err := rb.WriteMany([]int{1, 2, 3}); err != nil {
+				t.Errorf("failed to write to buffer: %s", err)
+				t.Fail()
+			}
+			if err := rb.PushFront(0); !errors.Is(err, test.err) {
+				t.Errorf("incorrect error on PushFront(), expected %v but got %v", test.err, err)
+				t.Fail()
+			}
+			if !reflect.DeepEqual(test.expected, rb.Read()) {
+				t.Errorf("incorrect result on Read(), expected %v but got %v", test.expected, rb.Read())
+				t.Fail()
+			}
+			if test.err == nil && !reflect.DeepEqual([]int{3}, evicted) {
+				t.Errorf("incorrect evicted values, expected %v but got %v", []int{3}, evicted)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestDeque(t *testing.T) {
+	rb, _ := New[string](3)
+	if _, err := rb.PopFront(); !errors.Is(err, ErrBufferEmpty) {
+		t.Errorf("incorrect error on PopFront(), expected %v but got %v", ErrBufferEmpty, err)
+		t.Fail()
+	}
+	if _, err := rb.PeekBack(); !errors.Is(err, ErrBufferEmpty) {
+		t.Errorf("incorrect error on PeekBack(), expected %v but got %v", ErrBufferEmpty, err)
+		t.Fail()
+	}
+
+	_ = rb.PushBack("b")
+	_ = rb.PushFront("a")
+	_ = rb.PushBack("c")
+	_ = rb.PushBack("d") // evicts "a" from the front
+	if !reflect.DeepEqual([]string{"b", "c", "d"}, rb.Read()) {
+		t.Errorf("incorrect result on Read(), expected %v but got %v", []string{"b", "c", "d"}, rb.Read())
+		t.Fail()
+	}
+
+	steps := []struct {
+		name     string
+		op       func() (string, error)
+		expected string
+	}{
+		{"PeekFront()", rb.PeekFront, "b"},
+		{"PeekBack()", rb.PeekBack, "d"},
+		{"PopBack()", rb.PopBack, "d"},
+		{"PopFront()", rb.PopFront, "b"},
+		{"PopBack()", rb.PopBack, "c"},
+	}
+	for _, step := range steps {
+		if value, err := step.op(); err != nil || value != step.expected {
+			t.Errorf("incorrect result on %s, expected %s but got %s (%v)", step.name, step.expected, value, err)
+			t.Fail()
+		}
+	}
+	if !rb.IsEmpty() {
+		t.Errorf("buffer should be empty after popping every element but IsEmpty() == %v", rb.IsEmpty())
+		t.Fail()
+	}
+}
@@ -0,0 +1,342 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package ringbuffer
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// Error handling statements for FileRing
+var (
+	ErrFileCorrupt = errors.New("failed to open file ring! The file header is " +
+		"corrupt or the file is not a ring buffer")
+	ErrFileTypeMismatch = errors.New("failed to open file ring! The file was " +
+		"written with a different buffer type")
+	ErrFileCapacityMismatch = errors.New("failed to open file ring! The file was " +
+		"written with a different capacity")
+)
+
+// fileHeaderSize is the size of the header in front of the slot array. It is a
+// multiple of 8, so every slot is aligned for its type
+const fileHeaderSize = 160
+
+// fileMagic identifies a file-backed ring buffer
+var fileMagic = [8]byte{'R', 'I', 'N', 'G', 'B', 'U', 'F', 0}
+
+// Offsets of the header fields. All fields are stored in little-endian byte order.
+//
+// The fields that never change after the file is created are followed by a checksum of
+// their own. The buffer state is stored in two state blocks that are written in turn,
+// each with a generation number and a checksum, so that a torn write of one block
+// always leaves the previous state intact in the other
+const (
+	fileVersion         = 2
+	offsetVersion       = 8
+	offsetTypeTag       = 12
+	offsetElementSize   = 16
+	offsetCapacity      = 24
+	offsetChecksum      = 32
+	fileChecksummedSize = offsetChecksum
+
+	offsetStateBlocks = 64 // Offset of the first state block
+	stateBlockSize    = 48
+
+	// Offsets within a state block
+	offsetGeneration     = 0
+	offsetWriteIndex     = 8
+	offsetElementCount   = 16
+	offsetOverwrites     = 24
+	offsetStateChecksum  = 32
+	stateChecksummedSize = offsetStateChecksum
+)
+
+// FileRing is a ring buffer whose header and slots live in a memory-mapped file, so that
+// its contents survive the process crashing: reopening the file resumes exactly where
+// the process left off.
+//
+// The file starts with a header holding the capacity and a tag identifying the buffer
+// type, followed by two alternating state blocks holding writeIndex and elementCount,
+// all protected by CRC-32 checksums. The slot array follows the header, with every
+// value stored in the native byte order of the machine.
+//
+// Every Write updates the slot before the state, and the state is written into the
+// older of the two state blocks, so a crash at any point leaves a valid state behind.
+// At worst, the value being written is lost, or it shows up in place of the oldest value
+// it overwrote. The data reaches the page cache immediately, which is enough to survive
+// a process crash; use Sync to also survive an operating system crash.
+type FileRing[T FixedType] struct {
+	file  *os.File
+	data  []byte // The whole mapped file
+	slots []T    // The slot array, pointing into data
+	mut   sync.RWMutex
+
+	capacity     int
+	elementCount int
+	writeIndex   int
+	overwrites   uint64
+	generation   uint64 // Generation of the newest state block
+}
+
+// OpenFileRing opens the file-backed ring buffer at path, creating it with the given
+// capacity if it does not exist. An existing file must have been created with the same
+// buffer type and capacity.
+//
+// Only one FileRing, in one process, may have the file open at a time.
+func OpenFileRing[T FixedType](path string, capacity int) (*FileRing[T], error) {
+	if capacity <= 0 {
+		return nil, ErrCapacityNegativeOrZero
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	var zero T
+	size := fileHeaderSize + capacity*int(unsafe.Sizeof(zero))
+	created := info.Size() == 0
+	if created {
+		if err = file.Truncate(int64(size)); err != nil {
+			file.Close()
+			return nil, err
+		}
+	} else if info.Size() < fileHeaderSize {
+		file.Close()
+		return nil, ErrFileCorrupt
+	}
+
+	mapped := int(info.Size())
+	if created {
+		mapped = size
+	}
+	data, err := syscall.Mmap(int(file.Fd()), 0, mapped, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	fr := &FileRing[T]{file: file, data: data, capacity: capacity}
+	if created {
+		copy(data, fileMagic[:])
+		binary.LittleEndian.PutUint32(data[offsetVersion:], fileVersion)
+		binary.LittleEndian.PutUint32(data[offsetTypeTag:], typeTag[T]())
+		binary.LittleEndian.PutUint32(data[offsetElementSize:], uint32(unsafe.Sizeof(zero)))
+		binary.LittleEndian.PutUint64(data[offsetCapacity:], uint64(capacity))
+		binary.LittleEndian.PutUint32(data[offsetChecksum:], crc32.ChecksumIEEE(data[:fileChecksummedSize]))
+		fr.writeHeader()
+	} else if err = fr.readHeader(mapped); err != nil {
+		fr.Close()
+		return nil, err
+	}
+	fr.slots = unsafe.Slice((*T)(unsafe.Pointer(&data[fileHeaderSize])), capacity)
+	return fr, nil
+}
+
+// readHeader validates the header of an existing file of the given size and loads the
+// buffer state from the newest valid state block
+func (fr *FileRing[T]) readHeader(size int) error {
+	header := fr.data[:fileHeaderSize]
+	if [8]byte(header[:8]) != fileMagic ||
+		binary.LittleEndian.Uint32(header[offsetVersion:]) != fileVersion ||
+		binary.LittleEndian.Uint32(header[offsetChecksum:]) != crc32.ChecksumIEEE(header[:fileChecksummedSize]) {
+		return ErrFileCorrupt
+	}
+
+	var zero T
+	if binary.LittleEndian.Uint32(header[offsetTypeTag:]) != typeTag[T]() ||
+		binary.LittleEndian.Uint32(header[offsetElementSize:]) != uint32(unsafe.Sizeof(zero)) {
+		return ErrFileTypeMismatch
+	}
+	if binary.LittleEndian.Uint64(header[offsetCapacity:]) != uint64(fr.capacity) {
+		return ErrFileCapacityMismatch
+	}
+	if size != fileHeaderSize+fr.capacity*int(unsafe.Sizeof(zero)) {
+		return ErrFileCorrupt
+	}
+
+	valid := false
+	for i := 0; i < 2; i++ {
+		block := fr.stateBlock(i)
+		generation := binary.LittleEndian.Uint64(block[offsetGeneration:])
+		writeIndex := binary.LittleEndian.Uint64(block[offsetWriteIndex:])
+		elementCount := binary.LittleEndian.Uint64(block[offsetElementCount:])
+		if binary.LittleEndian.Uint32(block[offsetStateChecksum:]) != crc32.ChecksumIEEE(block[:stateChecksummedSize]) ||
+			writeIndex >= uint64(fr.capacity) || elementCount > uint64(fr.capacity) ||
+			(valid && generation <= fr.generation) {
+			continue
+		}
+		valid = true
+		fr.generation = generation
+		fr.writeIndex = int(writeIndex)
+		fr.elementCount = int(elementCount)
+		fr.overwrites = binary.LittleEndian.Uint64(block[offsetOverwrites:])
+	}
+	if !valid {
+		return ErrFileCorrupt
+	}
+	return nil
+}
+
+// stateBlock returns state block i of the header
+func (fr *FileRing[T]) stateBlock(i int) []byte {
+	offset := offsetStateBlocks + i*stateBlockSize
+	return fr.data[offset : offset+stateBlockSize]
+}
+
+// writeHeader stores the buffer state in the older state block, under the next
+// generation number. It must be called with fr.mut held
+func (fr *FileRing[T]) writeHeader() {
+	fr.generation++
+	block := fr.stateBlock(int(fr.generation % 2))
+	binary.LittleEndian.PutUint64(block[offsetGeneration:], fr.generation)
+	binary.LittleEndian.PutUint64(block[offsetWriteIndex:], uint64(fr.writeIndex))
+	binary.LittleEndian.PutUint64(block[offsetElementCount:], uint64(fr.elementCount))
+	binary.LittleEndian.PutUint64(block[offsetOverwrites:], fr.overwrites)
+	binary.LittleEndian.PutUint32(block[offsetStateChecksum:], crc32.ChecksumIEEE(block[:stateChecksummedSize]))
+}
+
+// Write inserts one element into the buffer, overwriting the oldest element (without
+// error) if the buffer is full
+func (fr *FileRing[T]) Write(value T) {
+	fr.mut.Lock()
+	defer fr.mut.Unlock()
+	fr.write(value)
+	fr.writeHeader()
+}
+
+// WriteMany writes every value in order, updating the header once at the end. An error
+// is returned, and nothing is written, if there are more values than the buffer capacity
+// or if there are no values at all.
+func (fr *FileRing[T]) WriteMany(values []T) error {
+	fr.mut.Lock()
+	defer fr.mut.Unlock()
+
+	if len(values) > fr.capacity {
+		return ErrCapacityTooSmall
+	} else if len(values) == 0 {
+		return ErrDataLengthIsZero
+	}
+	for _, value := range values {
+		fr.write(value)
+	}
+	fr.writeHeader()
+	return nil
+}
+
+// write stores a value into the next slot without updating the header. It must be
+// called with fr.mut held
+func (fr *FileRing[T]) write(value T) {
+	fr.slots[fr.writeIndex] = value
+	fr.writeIndex = (fr.writeIndex + 1) % fr.capacity
+	if fr.elementCount < fr.capacity {
+		fr.elementCount++
+	} else {
+		fr.overwrites++
+	}
+}
+
+// Read returns the contents of the buffer in "First-In First-Out" (FIFO) order
+func (fr *FileRing[T]) Read() []T {
+	fr.mut.RLock()
+	defer fr.mut.RUnlock()
+
+	result := make([]T, fr.elementCount)
+	for i := range result {
+		result[i] = fr.slots[(fr.writeIndex+fr.capacity-fr.elementCount+i)%fr.capacity]
+	}
+	return result
+}
+
+// Reset deletes all data within the buffer but retains the same exact capacity
+func (fr *FileRing[T]) Reset() {
+	fr.mut.Lock()
+	defer fr.mut.Unlock()
+
+	var zero T
+	for i := range fr.slots {
+		fr.slots[i] = zero
+	}
+	fr.elementCount = 0
+	fr.writeIndex = 0
+	fr.writeHeader()
+}
+
+// Length returns the number of elements or values within the buffer.
+//
+// For getting the total capacity of the buffer, use Capacity()
+func (fr *FileRing[T]) Length() int {
+	fr.mut.RLock()
+	defer fr.mut.RUnlock()
+	return fr.elementCount
+}
+
+// Capacity returns the zero-indexed capacity of the ring buffer itself, as opposed to the
+// number of elements within the buffer.
+//
+// For getting the number of elements in a buffer, use Length()
+func (fr *FileRing[T]) Capacity() int {
+	return fr.capacity
+}
+
+// IsFull returns a boolean indicating if the number of elements or values of the buffer
+// equals the buffer capacity
+func (fr *FileRing[T]) IsFull() bool {
+	fr.mut.RLock()
+	defer fr.mut.RUnlock()
+	return fr.elementCount == fr.capacity
+}
+
+// IsEmpty returns a boolean indicating if the number of elements or values within the
+// buffer is zero
+func (fr *FileRing[T]) IsEmpty() bool {
+	fr.mut.RLock()
+	defer fr.mut.RUnlock()
+	return fr.elementCount == 0
+}
+
+// Overwrites returns the total number of values that were overwritten by Write because
+// the buffer was full. The count is persisted in the file.
+func (fr *FileRing[T]) Overwrites() uint64 {
+	fr.mut.RLock()
+	defer fr.mut.RUnlock()
+	return fr.overwrites
+}
+
+// Sync flushes the mapped file to stable storage, so that its contents also survive an
+// operating system crash or power loss
+func (fr *FileRing[T]) Sync() error {
+	fr.mut.RLock()
+	defer fr.mut.RUnlock()
+
+	_, _, errno := syscall.Syscall(sysMsync, uintptr(unsafe.Pointer(&fr.data[0])),
+		uintptr(len(fr.data)), syscall.MS_SYNC)
+	if errno != 0 {
+		return errno
+	}
+	return fr.file.Sync()
+}
+
+// Close unmaps and closes the file. The buffer must not be used afterwards.
+func (fr *FileRing[T]) Close() error {
+	fr.mut.Lock()
+	defer fr.mut.Unlock()
+
+	err := syscall.Munmap(fr.data)
+	fr.data = nil
+	fr.slots = nil
+	if closeErr := fr.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
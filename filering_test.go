@@ -0,0 +1,162 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package ringbuffer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileRing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "window.ring")
+
+	fr, err := OpenFileRing[float64](path, 3)
+	if err != nil {
+		t.Fatalf("failed to open file ring: %s", err)
+	}
+	if err = fr.WriteMany([]float64{1.5, 2.5, 3.5}); err != nil {
+		t.Errorf("failed to write to buffer: %s", err)
+		t.Fail()
+	}
+	fr.Write(4.5)
+	if err = fr.Sync(); err != nil {
+		t.Errorf("an error was not expected on Sync(): %s", err)
+		t.Fail()
+	}
+	if err = fr.Close(); err != nil {
+		t.Errorf("an error was not expected on Close(): %s", err)
+		t.Fail()
+	}
+
+	// Reopening the file must resume exactly where the buffer left off
+	fr, err = OpenFileRing[float64](path, 3)
+	if err != nil {
+		t.Fatalf("failed to reopen file ring: %s", err)
+	}
+	defer fr.Close()
+
+	expected := []float64{2.5, 3.5, 4.5}
+	if !reflect.DeepEqual(expected, fr.Read()) {
+		t.Errorf("incorrect result on Read() after reopening, expected %v but got %v", expected, fr.Read())
+		t.Fail()
+	}
+	if !fr.IsFull() || fr.Overwrites() != 1 {
+		t.Errorf("incorrect state after reopening, expected a full buffer with 1 overwrite")
+		t.Fail()
+	}
+	fr.Write(5.5)
+	expected = []float64{3.5, 4.5, 5.5}
+	if !reflect.DeepEqual(expected, fr.Read()) {
+		t.Errorf("incorrect result on Read(), expected %v but got %v", expected, fr.Read())
+		t.Fail()
+	}
+
+	fr.Reset()
+	if !fr.IsEmpty() || fr.Length() != 0 {
+		t.Errorf("buffer should be empty after Reset()")
+		t.Fail()
+	}
+}
+
+func TestOpenFileRingErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "window.ring")
+	fr, _ := OpenFileRing[int32](path, 4)
+	fr.Write(7)
+	fr.Close()
+
+	tests := []struct {
+		name     string
+		open     func() error
+		expected error
+	}{
+		{"zero capacity", func() error {
+			_, err := OpenFileRing[int32](path, 0)
+			return err
+		}, ErrCapacityNegativeOrZero},
+		{"type mismatch", func() error {
+			_, err := OpenFileRing[float32](path, 4)
+			return err
+		}, ErrFileTypeMismatch},
+		{"capacity mismatch", func() error {
+			_, err := OpenFileRing[int32](path, 5)
+			return err
+		}, ErrFileCapacityMismatch},
+		{"corrupt header", func() error {
+			corrupt := filepath.Join(dir, "corrupt.ring")
+			data, _ := os.ReadFile(path)
+			data[offsetCapacity] ^= 0xff
+			_ = os.WriteFile(corrupt, data, 0o644)
+			_, err := OpenFileRing[int32](corrupt, 4)
+			return err
+		}, ErrFileCorrupt},
+		{"corrupt state blocks", func() error {
+			corrupt := filepath.Join(dir, "corrupt-state.ring")
+			data, _ := os.ReadFile(path)
+			data[offsetStateBlocks+offsetWriteIndex] ^= 0xff
+			data[offsetStateBlocks+stateBlockSize+offsetWriteIndex] ^= 0xff
+			_ = os.WriteFile(corrupt, data, 0o644)
+			_, err := OpenFileRing[int32](corrupt, 4)
+			return err
+		}, ErrFileCorrupt},
+		{"not a ring", func() error {
+			other := filepath.Join(dir, "other.txt")
+			_ = os.WriteFile(other, []byte("hello"), 0o644)
+			_, err := OpenFileRing[int32](other, 4)
+			return err
+		}, ErrFileCorrupt},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.open(); !errors.Is(err, test.expected) {
+				t.Errorf("incorrect error, expected %v but got %v", test.expected, err)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestFileRingTornStateBlock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "window.ring")
+	fr, err := OpenFileRing[int64](path, 4)
+	if err != nil {
+		t.Fatalf("failed to open file ring: %s", err)
+	}
+	fr.WriteMany([]int64{1, 2})
+	fr.Write(3)
+	newest := int(fr.generation % 2)
+	fr.Close()
+
+	// Tearing the newest state block falls back to the state before the last write
+	data, _ := os.ReadFile(path)
+	data[offsetStateBlocks+newest*stateBlockSize+offsetElementCount] ^= 0xff
+	os.WriteFile(path, data, 0o644)
+
+	fr, err = OpenFileRing[int64](path, 4)
+	if err != nil {
+		t.Fatalf("failed to reopen file ring with a torn state block: %s", err)
+	}
+	expected := []int64{1, 2}
+	if !reflect.DeepEqual(expected, fr.Read()) {
+		t.Errorf("incorrect result on Read() after reopening, expected %v but got %v", expected, fr.Read())
+		t.Fail()
+	}
+
+	// Writing goes on from the recovered state and overwrites the torn block
+	fr.Write(4)
+	fr.Close()
+	fr, err = OpenFileRing[int64](path, 4)
+	if err != nil {
+		t.Fatalf("failed to reopen file ring: %s", err)
+	}
+	defer fr.Close()
+	expected = []int64{1, 2, 4}
+	if !reflect.DeepEqual(expected, fr.Read()) {
+		t.Errorf("incorrect result on Read() after writing, expected %v but got %v", expected, fr.Read())
+		t.Fail()
+	}
+}
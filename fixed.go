@@ -0,0 +1,39 @@
+package ringbuffer
+
+// FixedType provides constraints on the types that may be stored in the file-backed and
+// shared-memory ring buffers. Only numeric BufferType types with the same size on every
+// platform are allowed, so that their values can be laid out in a file as they are in
+// memory.
+type FixedType interface {
+	int16 | int32 | int64 |
+		byte | uint16 | uint32 | uint64 |
+		float32 | float64
+}
+
+// typeTag identifies a FixedType in the header of a file-backed ring buffer, so that a
+// file written with one type is not read back as another
+func typeTag[T FixedType]() uint32 {
+	var zero T
+	switch any(zero).(type) {
+	case int16:
+		return 1
+	case int32:
+		return 2
+	case int64:
+		return 3
+	case byte:
+		return 4
+	case uint16:
+		return 5
+	case uint32:
+		return 6
+	case uint64:
+		return 7
+	case float32:
+		return 8
+	case float64:
+		return 9
+	default:
+		return 0
+	}
+}
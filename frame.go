@@ -0,0 +1,184 @@
+package ringbuffer
+
+import (
+	"errors"
+	"math"
+	"sync"
+)
+
+// Error handling statements for FrameRing
+var (
+	ErrFrameWidthNegativeOrZero = errors.New("failed to create a new frame ring! " +
+		"Frame width must be greater than zero")
+	ErrFrameWidthMismatch = errors.New("failed to write to frame ring! The number of " +
+		"values in the frame does not match the frame width")
+)
+
+// FrameRing is a ring buffer of fixed-width frames, e.g. the six values of a 6-axis IMU
+// reading. All frames are stored in a single contiguous backing array, so the values of
+// a frame are always written and evicted together and can never drift out of sync the
+// way separate per-channel buffers could.
+type FrameRing[T BufferType] struct {
+	// buffer holds capacity frames of width values each, frame after frame
+	buffer       []T
+	mut          sync.RWMutex
+	width        int // Number of values in each frame
+	capacity     int // Total number of frames in the buffer
+	elementCount int // Number of frames stored within the buffer
+	writeIndex   int // The next frame to write into the buffer
+}
+
+// NewFrameRing creates a new frame ring holding a fixed, zero-indexed capacity of
+// frames, each made of width values
+func NewFrameRing[T BufferType](capacity, width int) (*FrameRing[T], error) {
+	if capacity <= 0 {
+		return nil, ErrCapacityNegativeOrZero
+	}
+	if width <= 0 {
+		return nil, ErrFrameWidthNegativeOrZero
+	}
+
+	return &FrameRing[T]{
+		buffer:   make([]T, capacity*width),
+		width:    width,
+		capacity: capacity,
+	}, nil
+}
+
+// WriteFrame inserts one frame into the buffer, overwriting the oldest frame (without
+// error) if the buffer is full. The frame is copied, so the caller may reuse it. An
+// error is returned if the frame does not hold exactly Width() values.
+func (fr *FrameRing[T]) WriteFrame(frame []T) error {
+	if len(frame) != fr.width {
+		return ErrFrameWidthMismatch
+	}
+
+	fr.mut.Lock()
+	defer fr.mut.Unlock()
+
+	copy(fr.frame(fr.writeIndex), frame)
+
+	fr.writeIndex = (fr.writeIndex + 1) % fr.capacity
+	if fr.elementCount < fr.capacity {
+		fr.elementCount++
+	}
+	return nil
+}
+
+// Frame returns a copy of the frame at logical index i, where index 0 is the oldest
+// frame. An error is returned if i is out of range.
+func (fr *FrameRing[T]) Frame(i int) ([]T, error) {
+	fr.mut.RLock()
+	defer fr.mut.RUnlock()
+
+	if i < 0 || i >= fr.elementCount {
+		return nil, ErrIndexOutOfRange
+	}
+	result := make([]T, fr.width)
+	copy(result, fr.frame(fr.index(i)))
+	return result, nil
+}
+
+// Frames returns a copy of every frame in "First-In First-Out" (FIFO) order
+func (fr *FrameRing[T]) Frames() [][]T {
+	fr.mut.RLock()
+	defer fr.mut.RUnlock()
+
+	// Copy all frames into one allocation and slice it up per frame
+	values := make([]T, fr.elementCount*fr.width)
+	result := make([][]T, fr.elementCount)
+	for i := range result {
+		result[i] = values[i*fr.width : (i+1)*fr.width : (i+1)*fr.width]
+		copy(result[i], fr.frame(fr.index(i)))
+	}
+	return result
+}
+
+// Column returns the values of column col of every frame in FIFO order. An error is
+// returned if col is out of range.
+func (fr *FrameRing[T]) Column(col int) ([]T, error) {
+	fr.mut.RLock()
+	defer fr.mut.RUnlock()
+
+	if col < 0 || col >= fr.width {
+		return nil, ErrIndexOutOfRange
+	}
+	result := make([]T, fr.elementCount)
+	for i := range result {
+		result[i] = fr.frame(fr.index(i))[col]
+	}
+	return result, nil
+}
+
+// ColumnStats returns the statistics of column col over every frame in the buffer.
+//
+// An error is returned if col is out of range, if the buffer type is not numeric, or if
+// the buffer contains no frames.
+func (fr *FrameRing[T]) ColumnStats(col int) (Stats, error) {
+	fr.mut.RLock()
+	defer fr.mut.RUnlock()
+
+	if col < 0 || col >= fr.width {
+		return Stats{}, ErrIndexOutOfRange
+	}
+	var zero T
+	if _, ok := toFloat64(zero); !ok {
+		return Stats{}, ErrNotNumeric
+	}
+	if fr.elementCount == 0 {
+		return Stats{}, ErrBufferEmpty
+	}
+
+	s := Stats{Min: math.Inf(1), Max: math.Inf(-1)}
+	for i := 0; i < fr.elementCount; i++ {
+		value, _ := toFloat64(fr.frame(fr.index(i))[col])
+		s.add(value)
+	}
+	s.Mean = s.Sum / float64(s.Count)
+	return s, nil
+}
+
+// Reset deletes all frames within the buffer by re-allocation but retains the same
+// exact capacity and width
+func (fr *FrameRing[T]) Reset() {
+	fr.mut.Lock()
+	defer fr.mut.Unlock()
+
+	fr.buffer = make([]T, fr.capacity*fr.width)
+	fr.elementCount = 0
+	fr.writeIndex = 0
+}
+
+// Length returns the number of frames within the buffer.
+//
+// For getting the total capacity of the buffer, use Capacity()
+func (fr *FrameRing[T]) Length() int {
+	fr.mut.RLock()
+	defer fr.mut.RUnlock()
+	return fr.elementCount
+}
+
+// Capacity returns the number of frames the buffer can hold, as opposed to the number
+// of frames within the buffer.
+//
+// For getting the number of frames in a buffer, use Length()
+func (fr *FrameRing[T]) Capacity() int {
+	fr.mut.RLock()
+	defer fr.mut.RUnlock()
+	return fr.capacity
+}
+
+// Width returns the number of values in each frame
+func (fr *FrameRing[T]) Width() int {
+	return fr.width
+}
+
+// index maps a logical frame index, where 0 is the oldest frame, onto the buffer
+func (fr *FrameRing[T]) index(i int) int {
+	return (fr.writeIndex + fr.capacity - fr.elementCount + i) % fr.capacity
+}
+
+// frame returns the slice of the backing array holding the frame at index
+func (fr *FrameRing[T]) frame(index int) []T {
+	return fr.buffer[index*fr.width : (index+1)*fr.width]
+}
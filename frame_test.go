@@ -0,0 +1,119 @@
+package ringbuffer
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNewFrameRing(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		width    int
+		expected error
+	}{
+		{"zero capacity", 0, 3, ErrCapacityNegativeOrZero},
+		{"zero width", 3, 0, ErrFrameWidthNegativeOrZero},
+		{"valid", 3, 6, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewFrameRing[float64](test.capacity, test.width); !errors.Is(err, test.expected) {
+				t.Errorf("incorrect error, expected %v but got %v", test.expected, err)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestFrameRing(t *testing.T) {
+	fr, _ := NewFrameRing[int](2, 3)
+	if err := fr.WriteFrame([]int{1, 2}); !errors.Is(err, ErrFrameWidthMismatch) {
+		t.Errorf("incorrect error on WriteFrame(), expected %v but got %v", ErrFrameWidthMismatch, err)
+		t.Fail()
+	}
+	for _, frame := range [][]int{{1, 10, 100}, {2, 20, 200}, {3, 30, 300}} {
+		if err := fr.WriteFrame(frame); err != nil {
+			t.Errorf("failed to write frame: %s", err)
+			t.Fail()
+		}
+	}
+
+	expected := [][]int{{2, 20, 200}, {3, 30, 300}}
+	if !reflect.DeepEqual(expected, fr.Frames()) {
+		t.Errorf("incorrect result on Frames(), expected %v but got %v", expected, fr.Frames())
+		t.Fail()
+	}
+	if frame, err := fr.Frame(1); err != nil || !reflect.DeepEqual(expected[1], frame) {
+		t.Errorf("incorrect result on Frame(1), expected %v but got %v (%v)", expected[1], frame, err)
+		t.Fail()
+	}
+	if column, err := fr.Column(1); err != nil || !reflect.DeepEqual([]int{20, 30}, column) {
+		t.Errorf("incorrect result on Column(1), expected %v but got %v (%v)", []int{20, 30}, column, err)
+		t.Fail()
+	}
+
+	s, err := fr.ColumnStats(2)
+	expectedStats := Stats{Count: 2, Sum: 500, Mean: 250, Min: 200, Max: 300}
+	if err != nil || s != expectedStats {
+		t.Errorf("incorrect result on ColumnStats(2), expected %+v but got %+v (%v)", expectedStats, s, err)
+		t.Fail()
+	}
+	if _, err := fr.ColumnStats(3); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("incorrect error on ColumnStats(3), expected %v but got %v", ErrIndexOutOfRange, err)
+		t.Fail()
+	}
+
+	fr.Reset()
+	if _, err := fr.ColumnStats(0); !errors.Is(err, ErrBufferEmpty) {
+		t.Errorf("incorrect error on ColumnStats() after Reset(), expected %v but got %v", ErrBufferEmpty, err)
+		t.Fail()
+	}
+
+	words, _ := NewFrameRing[string](2, 2)
+	_ = words.WriteFrame([]string{"a", "b"})
+	if _, err := words.ColumnStats(0); !errors.Is(err, ErrNotNumeric) {
+		t.Errorf("incorrect error on ColumnStats(), expected %v but got %v", ErrNotNumeric, err)
+		t.Fail()
+	}
+}
+
+func TestFrameRingColumnStatsEviction(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		values   []float64
+		expected Stats
+	}{
+		{"evicted infinity", 1, []float64{math.Inf(1), 1}, Stats{Count: 1, Sum: 1, Mean: 1, Min: 1, Max: 1}},
+		{"evicted NaN", 1, []float64{math.NaN(), 2}, Stats{Count: 1, Sum: 2, Mean: 2, Min: 2, Max: 2}},
+		{"evicted large value", 2, []float64{1e17, 1, 1}, Stats{Count: 2, Sum: 2, Mean: 1, Min: 1, Max: 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fr, _ := NewFrameRing[float64](test.capacity, 1)
+			for _, v := range test.values {
+				_ = fr.WriteFrame([]float64{v})
+			}
+			if s, err := fr.ColumnStats(0); err != nil || s != test.expected {
+				t.Errorf("incorrect result on ColumnStats(0), expected %+v but got %+v (%v)", test.expected, s, err)
+				t.Fail()
+			}
+		})
+	}
+
+	ints, _ := NewFrameRing[int64](2, 1)
+	for _, v := range []int64{1<<62 + 1, 1, 1} {
+		_ = ints.WriteFrame([]int64{v})
+	}
+	expected := Stats{Count: 2, Sum: 2, Mean: 1, Min: 1, Max: 1}
+	if s, err := ints.ColumnStats(0); err != nil || s != expected {
+		t.Errorf("incorrect result on ColumnStats(0) after evicting a large int64, expected %+v but got %+v (%v)",
+			expected, s, err)
+		t.Fail()
+	}
+}
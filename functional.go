@@ -0,0 +1,98 @@
+package ringbuffer
+
+import "errors"
+
+// Error handling statements for the functional helpers
+var (
+	ErrSameBuffer = errors.New("failed to map buffer! The source and destination " +
+		"buffers must be different")
+)
+
+// The helpers in this file walk a RingBuffer in "First-In First-Out" (FIFO) order while
+// holding its read lock, without copying its contents first. The callbacks are called
+// while the buffer is locked, so they must not call methods that write to the same
+// buffer.
+
+// Filter returns the elements of rb for which pred returns true, in FIFO order
+func Filter[T BufferType](rb *RingBuffer[T], pred func(value T) bool) []T {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+
+	var result []T
+	for i := 0; i < rb.elementCount; i++ {
+		if value := rb.buffer[rb.index(i)]; pred(value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// Map returns the result of calling fn with every element of rb, in FIFO order
+func Map[T BufferType, U any](rb *RingBuffer[T], fn func(value T) U) []U {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+
+	result := make([]U, 0, rb.elementCount)
+	for i := 0; i < rb.elementCount; i++ {
+		result = append(result, fn(rb.buffer[rb.index(i)]))
+	}
+	return result
+}
+
+// Reduce combines the elements of rb in FIFO order by calling fn with the result so far
+// and the next element, starting with the oldest element. An error is returned if the
+// buffer is empty.
+func Reduce[T BufferType](rb *RingBuffer[T], fn func(acc, value T) T) (T, error) {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+
+	if rb.elementCount == 0 {
+		var zero T
+		return zero, ErrBufferEmpty
+	}
+	acc := rb.buffer[rb.index(0)]
+	for i := 1; i < rb.elementCount; i++ {
+		acc = fn(acc, rb.buffer[rb.index(i)])
+	}
+	return acc, nil
+}
+
+// Fold combines the elements of rb in FIFO order by calling fn with the result so far
+// and the next element, starting with initial. Unlike Reduce, the result may be of any
+// type, and initial is returned as is if the buffer is empty.
+func Fold[T BufferType, A any](rb *RingBuffer[T], initial A, fn func(acc A, value T) A) A {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+
+	acc := initial
+	for i := 0; i < rb.elementCount; i++ {
+		acc = fn(acc, rb.buffer[rb.index(i)])
+	}
+	return acc
+}
+
+// MapInto writes the result of calling fn with every element of src into dst, in FIFO
+// order. Values are written the same way Write does, so the overflow policy of dst
+// applies: if dst rejects a value because it is full, ErrBufferFull is returned and the
+// remaining elements are skipped.
+//
+// Both buffers are locked for the duration of the call, so MapInto must not be called
+// concurrently with another MapInto going in the opposite direction between the same
+// two buffers.
+func MapInto[T, U BufferType](src *RingBuffer[T], dst *RingBuffer[U], fn func(value T) U) error {
+	if any(src) == any(dst) {
+		return ErrSameBuffer
+	}
+
+	src.mut.RLock()
+	defer src.mut.RUnlock()
+	dst.mut.Lock()
+	defer dst.mut.Unlock()
+
+	for i := 0; i < src.elementCount; i++ {
+		if err := dst.write(fn(src.buffer[src.index(i)])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
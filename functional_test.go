@@ -0,0 +1,83 @@
+package ringbuffer
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	rb := newWrapped(t)
+	expected := []int{2, 4}
+	if result := Filter(rb, func(value int) bool { return value%2 == 0 }); !reflect.DeepEqual(expected, result) {
+		t.Errorf("incorrect result on Filter(), expected %v but got %v", expected, result)
+		t.Fail()
+	}
+}
+
+func TestMap(t *testing.T) {
+	rb := newWrapped(t)
+	expected := []string{"1", "2", "3", "4", "5"}
+	if result := Map(rb, strconv.Itoa); !reflect.DeepEqual(expected, result) {
+		t.Errorf("incorrect result on Map(), expected %v but got %v", expected, result)
+		t.Fail()
+	}
+}
+
+func TestReduce(t *testing.T) {
+	rb := newWrapped(t)
+	sum, err := Reduce(rb, func(acc, value int) int { return acc + value })
+	if err != nil || sum != 15 {
+		t.Errorf("incorrect result on Reduce(), expected %d but got %d (%v)", 15, sum, err)
+		t.Fail()
+	}
+
+	empty, _ := New[int](3)
+	if _, err := Reduce(empty, func(acc, value int) int { return acc + value }); !errors.Is(err, ErrBufferEmpty) {
+		t.Errorf("incorrect error on Reduce(), expected %v but got %v", ErrBufferEmpty, err)
+		t.Fail()
+	}
+}
+
+func TestFold(t *testing.T) {
+	rb := newWrapped(t)
+	result := Fold(rb, "", func(acc string, value int) string { return acc + strconv.Itoa(value) })
+	if result != "12345" {
+		t.Errorf("incorrect result on Fold(), expected %s but got %s", "12345", result)
+		t.Fail()
+	}
+}
+
+func TestMapInto(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []float64
+		err      error
+	}{
+		{"overwrite", nil, []float64{3, 4, 5}, nil},
+		{"reject", []Option{WithOverflow(OverflowReject)}, []float64{1, 2, 3}, ErrBufferFull},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rb := newWrapped(t)
+			dst, _ := New[float64](3, test.opts...)
+			if err := MapInto(rb, dst, func(value int) float64 { return float64(value) }); !errors.Is(err, test.err) {
+				t.Errorf("incorrect error on MapInto(), expected %v but got %v", test.err, err)
+				t.Fail()
+			}
+			if !reflect.DeepEqual(test.expected, dst.Read()) {
+				t.Errorf("incorrect result on Read(), expected %v but got %v", test.expected, dst.Read())
+				t.Fail()
+			}
+		})
+	}
+
+	rb := newWrapped(t)
+	if err := MapInto(rb, rb, func(value int) int { return value }); !errors.Is(err, ErrSameBuffer) {
+		t.Errorf("incorrect error on MapInto(), expected %v but got %v", ErrSameBuffer, err)
+		t.Fail()
+	}
+}
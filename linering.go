@@ -0,0 +1,175 @@
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// Error handling statements for LineRing
+var (
+	ErrLineLengthNegativeOrZero = errors.New("failed to create a new line ring! The " +
+		"maximum line length must be greater than zero")
+)
+
+// LineRing keeps the last lines written to it, e.g. the output of a subprocess. It
+// implements io.Writer and splits the incoming bytes into lines, so it can be used as
+// the Stdout or Stderr of an exec.Cmd or as the destination of io.Copy.
+//
+// Lines are split on "\n" and a trailing "\r" is removed. A line may arrive across any
+// number of writes; it is only stored once its newline arrives, or when Flush is
+// called. Lines longer than the maximum line length are truncated.
+type LineRing struct {
+	mut        sync.Mutex
+	lines      *RingBuffer[string]
+	maxLineLen int
+	partial    []byte            // The line being written, up to maxLineLen bytes
+	filter     func(string) bool // Lines for which filter returns false are dropped
+}
+
+// NewLineRing creates a line ring that keeps the last lines lines of at most maxLineLen
+// bytes each. Any options are passed on to the ring buffer storing the lines, e.g.
+// WithClock to record the time every line was completed.
+func NewLineRing(lines, maxLineLen int, opts ...Option) (*LineRing, error) {
+	if maxLineLen <= 0 {
+		return nil, ErrLineLengthNegativeOrZero
+	}
+	rb, err := New[string](lines, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &LineRing{lines: rb, maxLineLen: maxLineLen}, nil
+}
+
+// Write splits p into lines and stores every completed line. It always consumes all of
+// p unless the ring buffer rejects a line, e.g. because it was created with
+// WithOverflow(OverflowReject) and is full, in which case that error is returned.
+func (lr *LineRing) Write(p []byte) (int, error) {
+	lr.mut.Lock()
+	defer lr.mut.Unlock()
+
+	n := 0
+	for len(p) > 0 {
+		end := bytes.IndexByte(p, '\n')
+		if end < 0 {
+			lr.appendPartial(p)
+			return n + len(p), nil
+		}
+		lr.appendPartial(p[:end])
+		if err := lr.store(); err != nil {
+			return n, err
+		}
+		n += end + 1
+		p = p[end+1:]
+	}
+	return n, nil
+}
+
+// appendPartial adds b to the line being written, discarding anything beyond the
+// maximum line length. It must be called with lr.mut held
+func (lr *LineRing) appendPartial(b []byte) {
+	if room := lr.maxLineLen - len(lr.partial); len(b) > room {
+		b = b[:room]
+	}
+	lr.partial = append(lr.partial, b...)
+}
+
+// store writes the line being written into the ring buffer. It must be called with
+// lr.mut held
+func (lr *LineRing) store() error {
+	line := string(bytes.TrimSuffix(lr.partial, []byte{'\r'}))
+	lr.partial = lr.partial[:0]
+	if lr.filter != nil && !lr.filter(line) {
+		return nil
+	}
+	return lr.lines.TryWrite(line)
+}
+
+// SetFilter makes the ring store only the lines for which filter returns true, e.g. the
+// lines matching a regular expression. Lines are filtered after truncation, and only
+// lines completed after the call are affected. A nil filter stores every line again.
+func (lr *LineRing) SetFilter(filter func(line string) bool) {
+	lr.mut.Lock()
+	defer lr.mut.Unlock()
+
+	lr.filter = filter
+}
+
+// Flush stores the line being written even though its newline has not arrived yet, e.g.
+// once a subprocess has exited. It does nothing if no partial line is pending.
+func (lr *LineRing) Flush() error {
+	lr.mut.Lock()
+	defer lr.mut.Unlock()
+
+	if len(lr.partial) == 0 {
+		return nil
+	}
+	return lr.store()
+}
+
+// DropBefore deletes the stored lines completed before t and returns the number of
+// deleted lines, e.g. to keep only the lines of the last few minutes. It only works if
+// the line ring was created with WithClock; otherwise nothing is deleted.
+func (lr *LineRing) DropBefore(t time.Time) int {
+	rb := lr.lines
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+
+	if rb.stamps == nil {
+		return 0
+	}
+	// Lines are stamped in the order they are completed, so the old ones are in front
+	n := 0
+	for n < rb.elementCount && rb.stamps[rb.index(n)].Before(t) {
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return rb.compact(func(i int, _ string) bool { return i >= n })
+}
+
+// Lines returns the stored lines, oldest first, without their newlines. A partial line
+// that has not been completed or flushed is not included.
+func (lr *LineRing) Lines() []string {
+	return lr.lines.Read()
+}
+
+// WriteTo writes the stored lines to w, oldest first, each followed by a newline. It
+// implements io.WriterTo.
+func (lr *LineRing) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	lr.lines.Snapshot().Range(func(i int, line string) bool {
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+		return true
+	})
+	return buf.WriteTo(w)
+}
+
+// Snapshot returns an immutable view of the stored lines, e.g. to read them together
+// with their timestamps
+func (lr *LineRing) Snapshot() *Snapshot[string] {
+	return lr.lines.Snapshot()
+}
+
+// Length returns the number of stored lines
+func (lr *LineRing) Length() int {
+	return lr.lines.Length()
+}
+
+// Capacity returns the maximum number of stored lines
+func (lr *LineRing) Capacity() int {
+	return lr.lines.Capacity()
+}
+
+// Reset deletes all stored lines and any partial line
+func (lr *LineRing) Reset() {
+	lr.mut.Lock()
+	defer lr.mut.Unlock()
+
+	lr.partial = lr.partial[:0]
+	lr.lines.Reset()
+}
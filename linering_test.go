@@ -0,0 +1,145 @@
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewLineRingErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		lines      int
+		maxLineLen int
+		expected   error
+	}{
+		{"zero lines", 0, 80, ErrCapacityNegativeOrZero},
+		{"zero line length", 10, 0, ErrLineLengthNegativeOrZero},
+		{"valid", 10, 80, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewLineRing(test.lines, test.maxLineLen)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("incorrect error, expected %v but got %v", test.expected, err)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestLineRingWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		writes   []string
+		expected []string
+	}{
+		{"single write", []string{"a\nb\nc\n"}, []string{"a", "b", "c"}},
+		{"partial lines", []string{"he", "llo\nwo", "rld\n", "!"}, []string{"hello", "world"}},
+		{"crlf", []string{"a\r\n", "b\r", "\n"}, []string{"a", "b"}},
+		{"empty lines", []string{"\n\nx\n"}, []string{"", "", "x"}},
+		{"truncated", []string{"0123456789", "abc\nshort\n"}, []string{"01234567", "short"}},
+		{"overwritten", []string{"1\n2\n3\n4\n5\n"}, []string{"2", "3", "4", "5"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lr, _ := NewLineRing(4, 8)
+			for _, w := range test.writes {
+				n, err := lr.Write([]byte(w))
+				if err != nil || n != len(w) {
+					t.Fatalf("failed to write %q: wrote %d bytes (%v)", w, n, err)
+				}
+			}
+			if !reflect.DeepEqual(lr.Lines(), test.expected) {
+				t.Errorf("incorrect lines, expected %q but got %q", test.expected, lr.Lines())
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestLineRingFlush(t *testing.T) {
+	lr, _ := NewLineRing(4, 80)
+	io.Copy(lr, strings.NewReader("first\nno newline"))
+	if lr.Length() != 1 {
+		t.Errorf("incorrect length before Flush(), expected 1 but got %d", lr.Length())
+		t.Fail()
+	}
+
+	lr.Flush()
+	lr.Flush() // Nothing is pending anymore
+	expected := []string{"first", "no newline"}
+	if !reflect.DeepEqual(lr.Lines(), expected) {
+		t.Errorf("incorrect lines, expected %q but got %q", expected, lr.Lines())
+		t.Fail()
+	}
+
+	var out bytes.Buffer
+	n, err := lr.WriteTo(&out)
+	if err != nil || n != int64(out.Len()) || out.String() != "first\nno newline\n" {
+		t.Errorf("incorrect WriteTo(), got %q with n=%d (%v)", out.String(), n, err)
+		t.Fail()
+	}
+}
+
+func TestLineRingReject(t *testing.T) {
+	lr, _ := NewLineRing(2, 80, WithOverflow(OverflowReject))
+	n, err := lr.Write([]byte("a\nb\nc\n"))
+	if !errors.Is(err, ErrBufferFull) || n != 4 {
+		t.Errorf("incorrect result, expected 4 bytes and %v but got %d bytes and %v",
+			ErrBufferFull, n, err)
+		t.Fail()
+	}
+
+	lr.Reset()
+	if lr.Length() != 0 {
+		t.Errorf("incorrect length after Reset(), expected 0 but got %d", lr.Length())
+		t.Fail()
+	}
+}
+
+func TestLineRingSetFilter(t *testing.T) {
+	lr, _ := NewLineRing(4, 80)
+	lr.SetFilter(func(line string) bool { return strings.HasPrefix(line, "ERROR") })
+	io.WriteString(lr, "INFO start\nERROR disk full\nINFO retry\n")
+	lr.SetFilter(nil)
+	io.WriteString(lr, "INFO done\n")
+
+	expected := []string{"ERROR disk full", "INFO done"}
+	if !reflect.DeepEqual(lr.Lines(), expected) {
+		t.Errorf("incorrect lines, expected %q but got %q", expected, lr.Lines())
+		t.Fail()
+	}
+}
+
+func TestLineRingDropBefore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lr, _ := NewLineRing(4, 16, WithClock(func() time.Time { return now }))
+	for _, line := range []string{"a", "b", "c"} {
+		_, _ = lr.Write([]byte(line + "\n"))
+		now = now.Add(time.Minute)
+	}
+
+	// "a" and "b" were completed more than a minute before the last line
+	if dropped := lr.DropBefore(now.Add(-time.Minute)); dropped != 2 {
+		t.Errorf("incorrect result on DropBefore(), expected 2 dropped lines but got %d", dropped)
+		t.Fail()
+	}
+	if !reflect.DeepEqual([]string{"c"}, lr.Lines()) {
+		t.Errorf("incorrect lines after DropBefore(), expected %v but got %v", []string{"c"}, lr.Lines())
+		t.Fail()
+	}
+
+	plain, _ := NewLineRing(4, 16)
+	_, _ = plain.Write([]byte("a\n"))
+	if dropped := plain.DropBefore(now); dropped != 0 || plain.Length() != 1 {
+		t.Errorf("lines without timestamps must not be dropped, but %d were", dropped)
+		t.Fail()
+	}
+}
@@ -0,0 +1,211 @@
+package ringbuffer
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Error handling statements for the metrics Registry
+var (
+	ErrRegistryNameEmpty = errors.New("failed to register buffer! The buffer name " +
+		"must not be empty")
+	ErrRegistryNameTaken = errors.New("failed to register buffer! A buffer with the " +
+		"same name is already registered")
+)
+
+// Instrumented is implemented by the ring buffer types of this package that can be
+// exported through a Registry. Only types defined in this package satisfy it.
+type Instrumented interface {
+	metrics() bufferMetrics
+}
+
+// bufferMetrics is a point-in-time view of a buffer used to render the exposition
+type bufferMetrics struct {
+	length     int
+	capacity   int
+	overwrites uint64
+	stats      Stats
+	hasStats   bool // Set when the buffer is numeric and holds at least one value
+}
+
+// metrics takes a consistent view of the buffer for the metrics Registry
+func (rb *RingBuffer[T]) metrics() bufferMetrics {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+
+	m := bufferMetrics{
+		length:     rb.elementCount,
+		capacity:   rb.capacity,
+		overwrites: rb.overwrites,
+	}
+	if s, err := rb.stats(); err == nil {
+		m.stats = s
+		m.hasStats = true
+	}
+	return m
+}
+
+// Registry is a set of named ring buffers that renders their metrics in the Prometheus
+// text exposition format. Registry implements http.Handler, so it can be mounted
+// directly on a mux, e.g. http.Handle("/metrics", reg).
+//
+// For every buffer the occupancy, capacity and overwrite count are exported. Numeric
+// buffers that hold at least one value additionally export the mean, minimum and
+// maximum of the current window.
+type Registry struct {
+	mut     sync.Mutex
+	buffers map[string]Instrumented
+}
+
+// metricFamily describes a single metric exported for each registered buffer
+type metricFamily struct {
+	name  string
+	help  string
+	kind  string
+	value func(m bufferMetrics) float64
+	stats bool // Only exported when the buffer has window statistics
+}
+
+// metricFamilies lists every metric in the order they are rendered
+var metricFamilies = []metricFamily{
+	{
+		name:  "ringbuffer_elements",
+		help:  "Number of elements currently stored in the ring buffer.",
+		kind:  "gauge",
+		value: func(m bufferMetrics) float64 { return float64(m.length) },
+	},
+	{
+		name:  "ringbuffer_capacity",
+		help:  "Total capacity of the ring buffer.",
+		kind:  "gauge",
+		value: func(m bufferMetrics) float64 { return float64(m.capacity) },
+	},
+	{
+		name:  "ringbuffer_overwrites_total",
+		help:  "Total number of elements overwritten because the ring buffer was full.",
+		kind:  "counter",
+		value: func(m bufferMetrics) float64 { return float64(m.overwrites) },
+	},
+	{
+		name:  "ringbuffer_window_mean",
+		help:  "Mean of the values currently stored in the ring buffer.",
+		kind:  "gauge",
+		value: func(m bufferMetrics) float64 { return m.stats.Mean },
+		stats: true,
+	},
+	{
+		name:  "ringbuffer_window_min",
+		help:  "Smallest value currently stored in the ring buffer.",
+		kind:  "gauge",
+		value: func(m bufferMetrics) float64 { return m.stats.Min },
+		stats: true,
+	},
+	{
+		name:  "ringbuffer_window_max",
+		help:  "Largest value currently stored in the ring buffer.",
+		kind:  "gauge",
+		value: func(m bufferMetrics) float64 { return m.stats.Max },
+		stats: true,
+	},
+}
+
+// NewRegistry creates an empty metrics Registry
+func NewRegistry() *Registry {
+	return &Registry{buffers: make(map[string]Instrumented)}
+}
+
+// Register adds a buffer to the registry under the given name. The name is exported as
+// the value of the "buffer" label, so it must be unique within the registry.
+func (reg *Registry) Register(name string, buffer Instrumented) error {
+	reg.mut.Lock()
+	defer reg.mut.Unlock()
+
+	if name == "" {
+		return ErrRegistryNameEmpty
+	}
+	if _, ok := reg.buffers[name]; ok {
+		return ErrRegistryNameTaken
+	}
+	reg.buffers[name] = buffer
+	return nil
+}
+
+// Unregister removes the buffer registered under name. Nothing happens if no buffer is
+// registered under that name.
+func (reg *Registry) Unregister(name string) {
+	reg.mut.Lock()
+	defer reg.mut.Unlock()
+	delete(reg.buffers, name)
+}
+
+// ServeHTTP renders the metrics of every registered buffer in the Prometheus text
+// exposition format, ordered by buffer name
+func (reg *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(reg.String()))
+}
+
+// String renders the metrics of every registered buffer in the Prometheus text
+// exposition format, ordered by buffer name
+func (reg *Registry) String() string {
+	reg.mut.Lock()
+	names := make([]string, 0, len(reg.buffers))
+	for name := range reg.buffers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Collect every buffer once so all families render from the same view
+	collected := make([]bufferMetrics, len(names))
+	for i, name := range names {
+		collected[i] = reg.buffers[name].metrics()
+	}
+	reg.mut.Unlock()
+
+	var sb strings.Builder
+	for _, family := range metricFamilies {
+		header := false
+		for i, name := range names {
+			m := collected[i]
+			if family.stats && !m.hasStats {
+				continue
+			}
+			if !header {
+				sb.WriteString("# HELP " + family.name + " " + family.help + "\n")
+				sb.WriteString("# TYPE " + family.name + " " + family.kind + "\n")
+				header = true
+			}
+			sb.WriteString(family.name + `{buffer="` + escapeLabelValue(name) + `"} ` +
+				formatMetricValue(family.value(m)) + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// labelValueEscaper escapes label values as required by the exposition format
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabelValue escapes backslashes, double quotes and line feeds in a label value
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+// formatMetricValue formats a sample value, spelling out infinities and NaN the way
+// the exposition format expects them
+func formatMetricValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,84 @@
+package ringbuffer
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryRegister(t *testing.T) {
+	tests := []struct {
+		name     string
+		bufName  string
+		expected error
+	}{
+		{"empty name", "", ErrRegistryNameEmpty},
+		{"new name", "latency", nil},
+		{"duplicate name", "latency", ErrRegistryNameTaken},
+	}
+	reg := NewRegistry()
+	rb, _ := New[int](3)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := reg.Register(test.bufName, rb); !errors.Is(err, test.expected) {
+				t.Errorf("incorrect error on Register(), expected %v but got %v", test.expected, err)
+				t.Fail()
+			}
+		})
+	}
+
+	reg.Unregister("latency")
+	if err := reg.Register("latency", rb); err != nil {
+		t.Errorf("an error was not expected when registering after Unregister(): %s", err)
+		t.Fail()
+	}
+}
+
+func TestRegistryServeHTTP(t *testing.T) {
+	reg := NewRegistry()
+	numbers, _ := New[float64](3)
+	words, _ := New[string](2)
+	if err := numbers.WriteMany([]float64{1, 2, 3}); err != nil {
+		t.Errorf("failed to write to buffer: %s", err)
+		t.Fail()
+	}
+	numbers.Write(4)
+	words.Write("a")
+	_ = reg.Register("numbers", numbers)
+	_ = reg.Register(`wo"rds`, words)
+
+	recorder := httptest.NewRecorder()
+	reg.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	response := recorder.Result()
+	body, _ := io.ReadAll(response.Body)
+
+	if contentType := response.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("incorrect Content-Type, expected the text exposition format but got %s", contentType)
+		t.Fail()
+	}
+
+	expected := []string{
+		"# TYPE ringbuffer_elements gauge",
+		`ringbuffer_elements{buffer="numbers"} 3`,
+		`ringbuffer_elements{buffer="wo\"rds"} 1`,
+		`ringbuffer_capacity{buffer="numbers"} 3`,
+		"# TYPE ringbuffer_overwrites_total counter",
+		`ringbuffer_overwrites_total{buffer="numbers"} 1`,
+		`ringbuffer_window_mean{buffer="numbers"} 3`,
+		`ringbuffer_window_min{buffer="numbers"} 2`,
+		`ringbuffer_window_max{buffer="numbers"} 4`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("metrics output is missing line %q:\n%s", line, body)
+			t.Fail()
+		}
+	}
+	if strings.Contains(string(body), `ringbuffer_window_mean{buffer="wo\"rds"}`) {
+		t.Errorf("window statistics should not be exported for a non-numeric buffer:\n%s", body)
+		t.Fail()
+	}
+}
@@ -0,0 +1,5 @@
+package ringbuffer
+
+// sysMsync is the number of the msync system call. NetBSD renamed it to __msync13, which
+// the syscall package does not define; its number is the same on every architecture
+const sysMsync = 277
@@ -0,0 +1,8 @@
+//go:build linux || darwin || freebsd || openbsd || dragonfly
+
+package ringbuffer
+
+import "syscall"
+
+// sysMsync is the number of the msync system call
+const sysMsync = syscall.SYS_MSYNC
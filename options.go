@@ -0,0 +1,171 @@
+package ringbuffer
+
+import (
+	"errors"
+	"time"
+)
+
+// Error handling statements for options passed to New
+var (
+	ErrInvalidOverflowPolicy = errors.New("failed to create a new ring buffer! " +
+		"The overflow policy is unknown")
+	ErrNilClock = errors.New("failed to create a new ring buffer! The clock " +
+		"function must not be nil")
+	ErrNilEvictHook = errors.New("failed to create a new ring buffer! The eviction " +
+		"hook must not be nil")
+	ErrEvictHookType = errors.New("failed to create a new ring buffer! The eviction " +
+		"hook must be a func(T) matching the buffer type")
+	ErrBackingSliceType = errors.New("failed to create a new ring buffer! The " +
+		"backing slice must be a []T matching the buffer type")
+	ErrBackingSliceTooSmall = errors.New("failed to create a new ring buffer! The " +
+		"backing slice is shorter than the buffer capacity")
+	ErrNilRegistry = errors.New("failed to create a new ring buffer! The metrics " +
+		"registry must not be nil")
+	ErrByteBudgetNegativeOrZero = errors.New("failed to create a new ring buffer! " +
+		"The byte budget must be greater than zero")
+	ErrNilSizeFunc = errors.New("failed to create a new ring buffer! The size " +
+		"function must not be nil")
+	ErrSizeFuncType = errors.New("failed to create a new ring buffer! The size " +
+		"function must be a func(T) int matching the buffer type")
+)
+
+// OverflowPolicy defines what happens when a value is written into a full buffer
+type OverflowPolicy int
+
+const (
+	// OverflowOverwrite overwrites the oldest value in the buffer. This is the default
+	OverflowOverwrite OverflowPolicy = iota
+	// OverflowReject keeps the existing values and rejects the new value instead
+	OverflowReject
+)
+
+// Option configures a ring buffer created by New. Options are validated by New before
+// the buffer is created, and the first invalid option is returned as an error.
+type Option func(*options) error
+
+// options collects the settings of every Option passed to New
+type options struct {
+	overflow    OverflowPolicy
+	now         func() time.Time
+	evictHook   any // Must be a func(T); checked by New once T is known
+	powerOfTwo  bool
+	backing     any // Must be a []T; checked by New once T is known
+	registry    *Registry
+	metricsName string
+	budget      int
+	sizeFunc    any // Must be a func(T) int; checked by New once T is known
+}
+
+// WithOverflow sets the policy applied when writing into a full buffer. The default is
+// OverflowOverwrite.
+func WithOverflow(policy OverflowPolicy) Option {
+	return func(o *options) error {
+		if policy != OverflowOverwrite && policy != OverflowReject {
+			return ErrInvalidOverflowPolicy
+		}
+		o.overflow = policy
+		return nil
+	}
+}
+
+// WithClock makes the buffer record the time every value was written, using now to
+// read the current time. The timestamps are available through Timestamps().
+func WithClock(now func() time.Time) Option {
+	return func(o *options) error {
+		if now == nil {
+			return ErrNilClock
+		}
+		o.now = now
+		return nil
+	}
+}
+
+// WithEvictHook registers a hook that is called with every value evicted from the
+// buffer, e.g. the oldest value when it is overwritten. hook must be a func(T) where T
+// is the buffer type.
+//
+// The hook is called while the buffer is locked, so it must not call methods on the
+// same buffer.
+func WithEvictHook(hook any) Option {
+	return func(o *options) error {
+		if hook == nil {
+			return ErrNilEvictHook
+		}
+		o.evictHook = hook
+		return nil
+	}
+}
+
+// WithPowerOfTwo rounds the capacity up to the next power of two
+func WithPowerOfTwo() Option {
+	return func(o *options) error {
+		o.powerOfTwo = true
+		return nil
+	}
+}
+
+// WithBackingSlice makes the buffer store its values in a preallocated slice instead of
+// allocating one. backing must be a []T where T is the buffer type, and must be at
+// least as long as the buffer capacity.
+//
+// The buffer keeps using backing across Reset, LoadFrom and ReadCSV. Once a Snapshot
+// has been taken, however, the next mutation copies the values into a newly allocated
+// array (copy-on-write), since the snapshot keeps reading backing; from then on the
+// buffer no longer uses backing.
+func WithBackingSlice(backing any) Option {
+	return func(o *options) error {
+		o.backing = backing
+		return nil
+	}
+}
+
+// WithMetrics registers the new buffer with a metrics Registry under the given name
+func WithMetrics(reg *Registry, name string) Option {
+	return func(o *options) error {
+		if reg == nil {
+			return ErrNilRegistry
+		}
+		o.registry = reg
+		o.metricsName = name
+		return nil
+	}
+}
+
+// WithByteBudget bounds the total size of all values in the buffer to budget bytes, in
+// addition to its capacity in elements. Writing a value evicts the oldest elements
+// until the total size fits within the budget (or, with OverflowReject, rejects the
+// value instead), and the current usage is reported by Bytes().
+//
+// Values are measured with the function set by WithSizeFunc. By default, strings are
+// measured by their length and all other types by their fixed size in memory.
+func WithByteBudget(budget int) Option {
+	return func(o *options) error {
+		if budget <= 0 {
+			return ErrByteBudgetNegativeOrZero
+		}
+		o.budget = budget
+		return nil
+	}
+}
+
+// WithSizeFunc sets the function used to measure values for WithByteBudget. sizeFunc
+// must be a func(T) int where T is the buffer type, and it must always return the same
+// size for the same value. It has no effect without WithByteBudget.
+func WithSizeFunc(sizeFunc any) Option {
+	return func(o *options) error {
+		if sizeFunc == nil {
+			return ErrNilSizeFunc
+		}
+		o.sizeFunc = sizeFunc
+		return nil
+	}
+}
+
+// nextPowerOfTwo returns the smallest power of two that is greater or equal to n
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
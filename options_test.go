@@ -0,0 +1,185 @@
+package ringbuffer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected error
+	}{
+		{"invalid overflow policy", []Option{WithOverflow(OverflowPolicy(42))}, ErrInvalidOverflowPolicy},
+		{"nil clock", []Option{WithClock(nil)}, ErrNilClock},
+		{"nil evict hook", []Option{WithEvictHook(nil)}, ErrNilEvictHook},
+		{"evict hook type", []Option{WithEvictHook(func(string) {})}, ErrEvictHookType},
+		{"backing slice type", []Option{WithBackingSlice([]string{})}, ErrBackingSliceType},
+		{"backing slice too small", []Option{WithBackingSlice(make([]int, 2))}, ErrBackingSliceTooSmall},
+		{"nil registry", []Option{WithMetrics(nil, "buffer")}, ErrNilRegistry},
+		{"empty metrics name", []Option{WithMetrics(NewRegistry(), "")}, ErrRegistryNameEmpty},
+		{"valid", []Option{WithOverflow(OverflowReject), WithEvictHook(func(int) {})}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rb, err := New[int](3, test.opts...)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("incorrect error, expected %v but got %v", test.expected, err)
+				t.Fail()
+			}
+			if (err == nil) != (rb != nil) {
+				t.Errorf("a buffer should be returned if and only if there is no error")
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestWithOverflow(t *testing.T) {
+	rb, _ := New[int](2, WithOverflow(OverflowReject))
+	if err := rb.WriteMany([]int{1, 2}); err != nil {
+		t.Errorf("failed to write to buffer: %s", err)
+		t.Fail()
+	}
+	if err := rb.TryWrite(3); !errors.Is(err, ErrBufferFull) {
+		t.Errorf("incorrect error on TryWrite(), expected %v but got %v", ErrBufferFull, err)
+		t.Fail()
+	}
+	rb.Write(4)
+	if !reflect.DeepEqual([]int{1, 2}, rb.Read()) {
+		t.Errorf("a full buffer should reject new values, expected %v but got %v", []int{1, 2}, rb.Read())
+		t.Fail()
+	}
+
+	rb, _ = New[int](3, WithOverflow(OverflowReject))
+	rb.Write(1)
+	if err := rb.WriteMany([]int{2, 3, 4}); !errors.Is(err, ErrBufferFull) {
+		t.Errorf("incorrect error on WriteMany(), expected %v but got %v", ErrBufferFull, err)
+		t.Fail()
+	}
+	if rb.Length() != 1 {
+		t.Errorf("a rejected WriteMany() should write nothing, expected %d elements but got %d", 1, rb.Length())
+		t.Fail()
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	clock := func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	rb, _ := New[string](2, WithClock(clock))
+	if err := rb.WriteMany([]string{"a", "b"}); err != nil {
+		t.Errorf("failed to write to buffer: %s", err)
+		t.Fail()
+	}
+	snap := rb.Snapshot()
+	rb.Write("c")
+
+	expected := []time.Time{start.Add(2 * time.Second), start.Add(3 * time.Second)}
+	if !reflect.DeepEqual(expected, rb.Timestamps()) {
+		t.Errorf("incorrect result on Timestamps(), expected %v but got %v", expected, rb.Timestamps())
+		t.Fail()
+	}
+	expected = []time.Time{start.Add(time.Second), start.Add(2 * time.Second)}
+	if !reflect.DeepEqual(expected, snap.Timestamps()) {
+		t.Errorf("incorrect snapshot timestamps, expected %v but got %v", expected, snap.Timestamps())
+		t.Fail()
+	}
+
+	rb, _ = New[string](2)
+	if rb.Timestamps() != nil {
+		t.Errorf("a buffer without a clock should not record timestamps but got %v", rb.Timestamps())
+		t.Fail()
+	}
+}
+
+func TestWithEvictHook(t *testing.T) {
+	var evicted []int
+	rb, _ := New[int](2, WithEvictHook(func(value int) { evicted = append(evicted, value) }))
+	if err := rb.WriteMany([]int{1, 2}); err != nil {
+		t.Errorf("failed to write to buffer: %s", err)
+		t.Fail()
+	}
+	rb.Write(3)
+	rb.Write(4)
+	if !reflect.DeepEqual([]int{1, 2}, evicted) {
+		t.Errorf("incorrect evicted values, expected %v but got %v", []int{1, 2}, evicted)
+		t.Fail()
+	}
+}
+
+func TestWithPowerOfTwo(t *testing.T) {
+	tests := []struct {
+		capacity int
+		expected int
+	}{
+		{1, 1},
+		{3, 4},
+		{8, 8},
+		{9, 16},
+	}
+
+	for _, test := range tests {
+		rb, _ := New[int](test.capacity, WithPowerOfTwo())
+		if rb.Capacity() != test.expected {
+			t.Errorf("incorrect capacity for %d, expected %d but got %d", test.capacity, test.expected, rb.Capacity())
+			t.Fail()
+		}
+	}
+}
+
+func TestWithBackingSlice(t *testing.T) {
+	backing := make([]int, 4)
+	rb, _ := New[int](3, WithBackingSlice(backing))
+	rb.Write(7)
+	if backing[0] != 7 {
+		t.Errorf("buffer should write into the backing slice, expected %d but got %d", 7, backing[0])
+		t.Fail()
+	}
+
+	// Reset and ReadCSV reuse the backing slice instead of allocating a new one
+	rb.Reset()
+	rb.Write(8)
+	if backing[0] != 8 {
+		t.Errorf("buffer should keep the backing slice after Reset(), expected %d but got %d", 8, backing[0])
+		t.Fail()
+	}
+	if err := rb.ReadCSV(strings.NewReader("value\n1\n2\n")); err != nil {
+		t.Fatalf("failed to read CSV: %s", err)
+	}
+	if !reflect.DeepEqual([]int{1, 2, 0, 0}, backing) {
+		t.Errorf("buffer should keep the backing slice after ReadCSV(), expected %v but got %v",
+			[]int{1, 2, 0, 0}, backing)
+		t.Fail()
+	}
+
+	// A write after a snapshot copies the values and leaves the backing slice to the
+	// snapshot
+	snap := rb.Snapshot()
+	rb.Write(3)
+	if backing[2] != 0 || !reflect.DeepEqual([]int{1, 2}, snap.Read()) {
+		t.Errorf("a write after Snapshot() must not change the backing slice, got %v", backing)
+		t.Fail()
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	reg := NewRegistry()
+	if _, err := New[int](3, WithMetrics(reg, "latency")); err != nil {
+		t.Errorf("an error was not expected when registering the buffer: %s", err)
+		t.Fail()
+	}
+	if _, err := New[int](3, WithMetrics(reg, "latency")); !errors.Is(err, ErrRegistryNameTaken) {
+		t.Errorf("incorrect error, expected %v but got %v", ErrRegistryNameTaken, err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,402 @@
+package ringbuffer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// Error handling statements for saving and loading buffers
+var (
+	ErrSaveFileCorrupt = errors.New("failed to load buffer! The file is not a saved " +
+		"ring buffer or its checksum does not match")
+	ErrSaveFileTypeMismatch = errors.New("failed to load buffer! The file was saved " +
+		"from a buffer of a different type")
+	ErrAutosaveTrigger = errors.New("failed to create autosaver! The interval or the " +
+		"number of writes between saves must be greater than zero, and neither may " +
+		"be negative")
+)
+
+// Layout of a saved buffer. The header holds the magic, the format version, flags, the
+// capacity and number of elements of the buffer, and the name of its type. It is
+// followed by every value as a length and its text form, then by the write time of
+// every value if the buffer has a clock, and finally by a CRC-32 checksum of everything
+// before it. All fields are little-endian
+const (
+	saveVersion    = 1
+	saveHeaderSize = 32
+	saveStampsFlag = 1 // Set when the file holds the write time of every value
+)
+
+// saveMagic identifies a saved buffer
+var saveMagic = [8]byte{'R', 'I', 'N', 'G', 'S', 'A', 'V', 0}
+
+// SaveTo writes the contents of the buffer to the file at path, including the write
+// time of every element if the buffer was created with WithClock.
+//
+// The file is replaced atomically: the contents are written to a temporary file in the
+// same directory, synced to disk and then renamed over path, so after a crash path
+// holds either the previous or the new contents, never a mix of both. The buffer is
+// not locked while the file is written, as SaveTo works on a Snapshot.
+func (rb *RingBuffer[T]) SaveTo(path string) error {
+	s := rb.Snapshot()
+	var zero T
+	typeName := fmt.Sprintf("%T", zero)
+
+	var data bytes.Buffer
+	header := make([]byte, saveHeaderSize)
+	copy(header, saveMagic[:])
+	binary.LittleEndian.PutUint32(header[8:], saveVersion)
+	if s.stamps != nil {
+		binary.LittleEndian.PutUint32(header[12:], saveStampsFlag)
+	}
+	binary.LittleEndian.PutUint64(header[16:], uint64(s.capacity))
+	binary.LittleEndian.PutUint64(header[24:], uint64(s.elementCount))
+	data.Write(header)
+	data.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(typeName))))
+	data.WriteString(typeName)
+
+	var record []byte
+	s.Range(func(i int, value T) bool {
+		record = appendValue(record[:0], value)
+		data.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(record))))
+		data.Write(record)
+		return true
+	})
+	for _, stamp := range s.Timestamps() {
+		var nanos int64 // The zero time is stored as zero
+		if !stamp.IsZero() {
+			nanos = stamp.UnixNano()
+		}
+		data.Write(binary.LittleEndian.AppendUint64(nil, uint64(nanos)))
+	}
+	data.Write(binary.LittleEndian.AppendUint32(nil, crc32.ChecksumIEEE(data.Bytes())))
+
+	return writeFileAtomic(path, data.Bytes())
+}
+
+// writeFileAtomic replaces the file at path with data through a synced temporary file,
+// then syncs the directory so that the rename itself survives a crash
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly once the file has been renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir syncs the directory at dir, so that files created, truncated or renamed
+// within it survive a crash
+func syncDir(dir string) error {
+	// Directories can not be synced on Windows, where such changes are durable on their
+	// own
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// LoadFrom replaces the contents of the buffer with the contents of a file written by
+// SaveTo. The buffer keeps its own capacity and options, so it may differ from the
+// buffer that was saved, but ErrCapacityTooSmall is returned if the saved elements do
+// not all fit. With a byte budget, the oldest saved elements that do not fit within the
+// budget are dropped.
+//
+// Saved write times are restored if the buffer was created with WithClock; elements
+// saved without a write time are stamped with the current time. The buffer is left
+// unchanged if an error is returned.
+func (rb *RingBuffer[T]) LoadFrom(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	values, stamps, err := decodeSave[T](data)
+	if err != nil {
+		return err
+	}
+	return rb.replaceAll(values, stamps)
+}
+
+// replaceAll replaces the contents of the buffer with values, oldest first, as
+// documented by LoadFrom. stamps holds the write time of every value, or is nil
+func (rb *RingBuffer[T]) replaceAll(values []T, stamps []time.Time) error {
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+
+	if len(values) > rb.capacity {
+		return ErrCapacityTooSmall
+	}
+
+	first, total := 0, 0
+	if rb.budget > 0 {
+		for first = len(values); first > 0; first-- {
+			size := rb.sizeOf(values[first-1])
+			if total+size > rb.budget {
+				break
+			}
+			total += size
+		}
+	}
+
+	rb.zero()
+	copy(rb.buffer, values[first:])
+	if rb.stamps != nil {
+		for i := range values[first:] {
+			if stamps != nil {
+				rb.stamps[i] = stamps[first+i]
+			} else {
+				rb.stamps[i] = rb.now()
+			}
+		}
+	}
+	rb.bytes = total
+	rb.elementCount = len(values) - first
+	rb.writeIndex = rb.elementCount % rb.capacity
+	rb.mutated()
+	return nil
+}
+
+// decodeSave checks and decodes a file written by SaveTo. The write times are nil if
+// the file holds none
+func decodeSave[T BufferType](data []byte) ([]T, []time.Time, error) {
+	if len(data) < saveHeaderSize+8 || !bytes.Equal(data[:8], saveMagic[:]) {
+		return nil, nil, ErrSaveFileCorrupt
+	}
+	body, sum := data[:len(data)-4], data[len(data)-4:]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(sum) {
+		return nil, nil, ErrSaveFileCorrupt
+	}
+	if binary.LittleEndian.Uint32(body[8:]) != saveVersion {
+		return nil, nil, ErrSaveFileCorrupt
+	}
+	flags := binary.LittleEndian.Uint32(body[12:])
+	count := binary.LittleEndian.Uint64(body[24:])
+
+	// next returns the next n bytes of the body, or nil if it is too short
+	rest := body[saveHeaderSize:]
+	next := func(n uint64) []byte {
+		if uint64(len(rest)) < n {
+			return nil
+		}
+		b := rest[:n]
+		rest = rest[n:]
+		return b
+	}
+
+	length := next(4)
+	if length == nil {
+		return nil, nil, ErrSaveFileCorrupt
+	}
+	typeName := next(uint64(binary.LittleEndian.Uint32(length)))
+	var zero T
+	if typeName == nil {
+		return nil, nil, ErrSaveFileCorrupt
+	} else if string(typeName) != fmt.Sprintf("%T", zero) {
+		return nil, nil, ErrSaveFileTypeMismatch
+	}
+	// Every value takes at least four bytes, which bounds count before allocating
+	if count > uint64(len(rest))/4 {
+		return nil, nil, ErrSaveFileCorrupt
+	}
+
+	values := make([]T, 0, count)
+	for i := uint64(0); i < count; i++ {
+		length := next(4)
+		if length == nil {
+			return nil, nil, ErrSaveFileCorrupt
+		}
+		record := next(uint64(binary.LittleEndian.Uint32(length)))
+		if record == nil {
+			return nil, nil, ErrSaveFileCorrupt
+		}
+		value, err := parseValue[T](string(record))
+		if err != nil {
+			return nil, nil, ErrSaveFileCorrupt
+		}
+		values = append(values, value)
+	}
+
+	var stamps []time.Time
+	if flags&saveStampsFlag != 0 {
+		stamps = make([]time.Time, 0, count)
+		for i := uint64(0); i < count; i++ {
+			nanos := next(8)
+			if nanos == nil {
+				return nil, nil, ErrSaveFileCorrupt
+			}
+			var stamp time.Time
+			if n := int64(binary.LittleEndian.Uint64(nanos)); n != 0 {
+				stamp = time.Unix(0, n)
+			}
+			stamps = append(stamps, stamp)
+		}
+	}
+	if len(rest) != 0 {
+		return nil, nil, ErrSaveFileCorrupt
+	}
+	return values, stamps, nil
+}
+
+// Autosaver periodically saves a RingBuffer to a file with SaveTo, so that a service
+// restarts with its recent history intact. A save is triggered every interval, every
+// given number of changes, or both, but only if the buffer changed since the last save.
+// Every change to the contents counts: writes as well as removals, updates, pops,
+// Reset, LoadFrom and ReadCSV.
+//
+// Several autosavers may watch the same buffer, e.g. to save it to different files.
+type Autosaver[T BufferType] struct {
+	rb       *RingBuffer[T]
+	path     string
+	interval time.Duration
+	everyN   uint64
+	done     chan struct{}
+	err      error // The error of the last save; read by Wait once done is closed
+}
+
+// NewAutosaver creates an autosaver that saves rb to path every interval and every
+// everyN changes. Either trigger is disabled by passing zero, but not both.
+func NewAutosaver[T BufferType](rb *RingBuffer[T], path string, interval time.Duration,
+	everyN int) (*Autosaver[T], error) {
+	if interval < 0 || everyN < 0 || (interval == 0 && everyN == 0) {
+		return nil, ErrAutosaveTrigger
+	}
+	return &Autosaver[T]{
+		rb:       rb,
+		path:     path,
+		interval: interval,
+		everyN:   uint64(everyN),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// Start runs the autosaver in a new goroutine until ctx is canceled, at which point any
+// unsaved changes are saved one last time. Start must only be called once; use Wait to
+// wait for the autosaver to stop.
+func (a *Autosaver[T]) Start(ctx context.Context) {
+	// Changes made after Start returns must never be missed, so the mutation count and
+	// the watch channel are taken before the goroutine starts
+	var changed chan struct{}
+	if a.everyN > 0 {
+		changed = a.rb.watch()
+	}
+	go a.run(ctx, changed, a.rb.mutationCount())
+}
+
+// Wait blocks until the autosaver has stopped and returns the error of its last save,
+// or nil if it succeeded
+func (a *Autosaver[T]) Wait() error {
+	<-a.done
+	return a.err
+}
+
+// run saves the buffer whenever a trigger fires until ctx is canceled. saved is the
+// mutation count the buffer had when it was last saved
+func (a *Autosaver[T]) run(ctx context.Context, changed chan struct{}, saved uint64) {
+	defer close(a.done)
+	if changed != nil {
+		defer a.rb.unwatch(changed)
+	}
+
+	var tick <-chan time.Time
+	if a.interval > 0 {
+		ticker := time.NewTicker(a.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	save := func() {
+		mutations := a.rb.mutationCount()
+		if mutations == saved {
+			return
+		}
+		a.err = a.rb.SaveTo(a.path)
+		if a.err == nil {
+			saved = mutations
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			save()
+			return
+		case <-tick:
+			save()
+		case <-changed:
+			if a.rb.mutationCount()-saved >= a.everyN {
+				save()
+			}
+		}
+	}
+}
+
+// watch returns a new channel that is signalled after every change to the buffer, until
+// it is passed to unwatch. Signals are coalesced while nobody receives them
+func (rb *RingBuffer[T]) watch() chan struct{} {
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+
+	c := make(chan struct{}, 1)
+	rb.watchers = append(rb.watchers, c)
+	return c
+}
+
+// unwatch stops signalling a channel returned by watch
+func (rb *RingBuffer[T]) unwatch(c chan struct{}) {
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+
+	for i, w := range rb.watchers {
+		if w == c {
+			rb.watchers = append(rb.watchers[:i], rb.watchers[i+1:]...)
+			return
+		}
+	}
+}
+
+// mutated counts a change to the contents of the buffer and signals every watch channel
+// without blocking. It must be called with rb.mut held
+func (rb *RingBuffer[T]) mutated() {
+	rb.mutations++
+	for _, c := range rb.watchers {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// mutationCount returns the number of changes ever made to the buffer
+func (rb *RingBuffer[T]) mutationCount() uint64 {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+	return rb.mutations
+}
@@ -0,0 +1,292 @@
+package ringbuffer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveToLoadFrom(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "buffer")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	rb, _ := New[string](3, WithClock(clock))
+	for _, v := range []string{"a", "b,c", "", "d\n"} {
+		rb.Write(v)
+	}
+	if err := rb.SaveTo(path); err != nil {
+		t.Fatalf("failed to save: %s", err)
+	}
+
+	loaded, _ := New[string](4, WithClock(clock))
+	loaded.Write("old")
+	if err := loaded.LoadFrom(path); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	if !reflect.DeepEqual(loaded.Read(), rb.Read()) {
+		t.Errorf("incorrect contents, expected %v but got %v", rb.Read(), loaded.Read())
+		t.Fail()
+	}
+	for i, stamp := range rb.Timestamps() {
+		if !loaded.Timestamps()[i].Equal(stamp) {
+			t.Errorf("incorrect timestamp %d, expected %v but got %v", i, stamp,
+				loaded.Timestamps()[i])
+			t.Fail()
+		}
+	}
+
+	// The loaded buffer must keep working as usual
+	loaded.Write("e")
+	loaded.Write("f")
+	expected := []string{"", "d\n", "e", "f"}
+	if !reflect.DeepEqual(loaded.Read(), expected) {
+		t.Errorf("incorrect contents after writing, expected %v but got %v", expected,
+			loaded.Read())
+		t.Fail()
+	}
+
+	matches, _ := filepath.Glob(path + ".tmp-*")
+	if len(matches) != 0 {
+		t.Errorf("temporary files were left behind: %v", matches)
+		t.Fail()
+	}
+}
+
+func TestLoadFromErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "buffer")
+	rb, _ := New[float64](4)
+	rb.WriteMany([]float64{1.5, 2.5, 3.5})
+	if err := rb.SaveTo(path); err != nil {
+		t.Fatalf("failed to save: %s", err)
+	}
+	data, _ := os.ReadFile(path)
+
+	corrupt := filepath.Join(dir, "corrupt")
+	flipped := append([]byte(nil), data...)
+	flipped[len(flipped)-6] ^= 0xff
+	os.WriteFile(corrupt, flipped, 0o644)
+
+	truncated := filepath.Join(dir, "truncated")
+	os.WriteFile(truncated, data[:len(data)/2], 0o644)
+
+	tests := []struct {
+		name     string
+		load     func() error
+		expected error
+	}{
+		{"corrupt", func() error {
+			b, _ := New[float64](4)
+			return b.LoadFrom(corrupt)
+		}, ErrSaveFileCorrupt},
+		{"truncated", func() error {
+			b, _ := New[float64](4)
+			return b.LoadFrom(truncated)
+		}, ErrSaveFileCorrupt},
+		{"type mismatch", func() error {
+			b, _ := New[int](4)
+			return b.LoadFrom(path)
+		}, ErrSaveFileTypeMismatch},
+		{"too small", func() error {
+			b, _ := New[float64](2)
+			return b.LoadFrom(path)
+		}, ErrCapacityTooSmall},
+		{"missing", func() error {
+			b, _ := New[float64](4)
+			return b.LoadFrom(filepath.Join(dir, "missing"))
+		}, os.ErrNotExist},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.load(); !errors.Is(err, test.expected) {
+				t.Errorf("incorrect error, expected %v but got %v", test.expected, err)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestLoadFromByteBudget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "buffer")
+	rb, _ := New[string](4)
+	rb.WriteMany([]string{"aaa", "bb", "cc", "d"})
+	if err := rb.SaveTo(path); err != nil {
+		t.Fatalf("failed to save: %s", err)
+	}
+
+	loaded, _ := New[string](4, WithByteBudget(5))
+	if err := loaded.LoadFrom(path); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	expected := []string{"bb", "cc", "d"}
+	if !reflect.DeepEqual(loaded.Read(), expected) || loaded.Bytes() != 5 {
+		t.Errorf("incorrect contents, expected %v using 5 bytes but got %v using %d bytes",
+			expected, loaded.Read(), loaded.Bytes())
+		t.Fail()
+	}
+}
+
+func TestNewAutosaverErrors(t *testing.T) {
+	rb, _ := New[int](4)
+	tests := []struct {
+		name     string
+		interval time.Duration
+		everyN   int
+		expected error
+	}{
+		{"no trigger", 0, 0, ErrAutosaveTrigger},
+		{"negative interval", -time.Second, 1, ErrAutosaveTrigger},
+		{"negative writes", time.Second, -1, ErrAutosaveTrigger},
+		{"interval", time.Second, 0, nil},
+		{"writes", 0, 10, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewAutosaver(rb, "buffer", test.interval, test.everyN)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("incorrect error, expected %v but got %v", test.expected, err)
+				t.Fail()
+			}
+		})
+	}
+}
+
+// waitForFile polls until the file at path holds a buffer with the expected contents
+func waitForFile(t *testing.T, path string, expected []int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rb, _ := New[int](8)
+		if rb.LoadFrom(path) == nil && reflect.DeepEqual(rb.Read(), expected) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the file was not saved with %v in time", expected)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAutosaverEveryN(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "buffer")
+	rb, _ := New[int](8)
+	a, _ := NewAutosaver(rb, path, 0, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	a.Start(ctx)
+
+	rb.Write(1)
+	rb.Write(2)
+	rb.Write(3)
+	waitForFile(t, path, []int{1, 2, 3})
+
+	// Unsaved writes are saved when the autosaver stops
+	rb.Write(4)
+	cancel()
+	if err := a.Wait(); err != nil {
+		t.Fatalf("failed to autosave: %s", err)
+	}
+	waitForFile(t, path, []int{1, 2, 3, 4})
+}
+
+func TestAutosaverInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "buffer")
+	rb, _ := New[int](8)
+	a, _ := NewAutosaver(rb, path, time.Millisecond, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a.Start(ctx)
+
+	rb.Write(7)
+	waitForFile(t, path, []int{7})
+}
+
+func TestAutosaverError(t *testing.T) {
+	rb, _ := New[int](8)
+	a, _ := NewAutosaver(rb, filepath.Join(t.TempDir(), "missing", "buffer"), time.Hour, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	a.Start(ctx)
+
+	rb.Write(1)
+	cancel()
+	if err := a.Wait(); err == nil {
+		t.Errorf("an error was expected when saving into a missing directory")
+		t.Fail()
+	}
+}
+
+func TestAutosaverMutations(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(rb *RingBuffer[int])
+		expected []int
+	}{
+		{"RemoveAt", func(rb *RingBuffer[int]) { rb.RemoveAt(0) }, []int{2, 3, 4}},
+		{"RemoveIf", func(rb *RingBuffer[int]) { rb.RemoveIf(func(v int) bool { return v%2 == 0 }) }, []int{1, 3}},
+		{"Retain", func(rb *RingBuffer[int]) { rb.Retain(func(v int) bool { return v > 3 }) }, []int{4}},
+		{"Set", func(rb *RingBuffer[int]) { rb.Set(1, 9) }, []int{1, 9, 3, 4}},
+		{"Update", func(rb *RingBuffer[int]) { rb.Update(3, func(v int) int { return -v }) }, []int{1, 2, 3, -4}},
+		{"CompareAndSwap", func(rb *RingBuffer[int]) { rb.CompareAndSwap(2, 3, 0) }, []int{1, 2, 0, 4}},
+		{"PopFront", func(rb *RingBuffer[int]) { rb.PopFront() }, []int{2, 3, 4}},
+		{"PopBack", func(rb *RingBuffer[int]) { rb.PopBack() }, []int{1, 2, 3}},
+		{"Reset", func(rb *RingBuffer[int]) { rb.Reset() }, []int{}},
+		{"ReadCSV", func(rb *RingBuffer[int]) { rb.ReadCSV(strings.NewReader("value\n5\n6\n")) }, []int{5, 6}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "buffer")
+			rb, _ := New[int](8)
+			rb.WriteMany([]int{1, 2, 3, 4})
+			a, _ := NewAutosaver(rb, path, 0, 1)
+			ctx, cancel := context.WithCancel(context.Background())
+			a.Start(ctx)
+
+			// Every change is saved, not only writes
+			test.mutate(rb)
+			waitForFile(t, path, test.expected)
+			cancel()
+			if err := a.Wait(); err != nil {
+				t.Fatalf("failed to autosave: %s", err)
+			}
+		})
+	}
+}
+
+func TestAutosaverMultiple(t *testing.T) {
+	dir := t.TempDir()
+	rb, _ := New[int](8)
+	ctx, cancel := context.WithCancel(context.Background())
+	var savers []*Autosaver[int]
+	for _, name := range []string{"first", "second"} {
+		a, _ := NewAutosaver(rb, filepath.Join(dir, name), 0, 1)
+		a.Start(ctx)
+		savers = append(savers, a)
+	}
+
+	// Both autosavers see every change, so neither steals the signals of the other
+	for v := 1; v <= 3; v++ {
+		rb.Write(v)
+		waitForFile(t, filepath.Join(dir, "first"), []int{1, 2, 3}[:v])
+		waitForFile(t, filepath.Join(dir, "second"), []int{1, 2, 3}[:v])
+	}
+	cancel()
+	for _, a := range savers {
+		if err := a.Wait(); err != nil {
+			t.Fatalf("failed to autosave: %s", err)
+		}
+	}
+	if len(rb.watchers) != 0 {
+		t.Errorf("stopped autosavers must stop watching the buffer, %d still watch it", len(rb.watchers))
+		t.Fail()
+	}
+}
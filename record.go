@@ -0,0 +1,186 @@
+package ringbuffer
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+)
+
+// Error handling statements for RecordRing
+var (
+	ErrRecordTooLarge = errors.New("failed to append record! The record and its " +
+		"length prefix do not fit within the arena")
+)
+
+// recordHeaderSize is the size of the length prefix stored in front of every record
+const recordHeaderSize = 4
+
+// RecordRing stores variable-length records, such as log lines or encoded protobuf
+// messages, in a single contiguous byte arena instead of allocating every record
+// separately.
+//
+// Every record is stored as a 4-byte little-endian length prefix followed by the record
+// itself. Records wrap around the end of the arena like the values of a RingBuffer, and
+// when space is needed for a new record, whole records are evicted oldest first.
+type RecordRing struct {
+	arena      []byte
+	mut        sync.RWMutex
+	head       int    // Offset of the oldest record in the arena
+	tail       int    // Offset at which the next record is appended
+	used       int    // Number of bytes used by records, including their length prefixes
+	count      int    // Number of records stored within the arena
+	overwrites uint64 // Number of records evicted to make room for new records
+}
+
+// NewRecordRing creates a new record ring backed by an arena of size bytes. The largest
+// record that can be stored is size-4 bytes long, since every record also stores its
+// length.
+func NewRecordRing(size int) (*RecordRing, error) {
+	if size <= recordHeaderSize {
+		return nil, ErrCapacityNegativeOrZero
+	}
+	return &RecordRing{arena: make([]byte, size)}, nil
+}
+
+// Append copies rec into the arena as a new record, evicting the oldest records until
+// there is enough space for it. An error is returned if the record can never fit.
+func (rr *RecordRing) Append(rec []byte) error {
+	size := recordHeaderSize + len(rec)
+	if size > len(rr.arena) {
+		return ErrRecordTooLarge
+	}
+
+	rr.mut.Lock()
+	defer rr.mut.Unlock()
+
+	for len(rr.arena)-rr.used < size {
+		rr.evict()
+	}
+
+	var header [recordHeaderSize]byte
+	binary.LittleEndian.PutUint32(header[:], uint32(len(rec)))
+	rr.copyIn(rr.tail, header[:])
+	rr.copyIn((rr.tail+recordHeaderSize)%len(rr.arena), rec)
+
+	rr.tail = (rr.tail + size) % len(rr.arena)
+	rr.used += size
+	rr.count++
+	return nil
+}
+
+// Range calls fn for each record in "First-In First-Out" (FIFO) order. Iteration stops
+// early if fn returns false.
+//
+// The record passed to fn points into the arena whenever the record is contiguous, so
+// it is only valid until fn returns and must not be modified. Records that wrap around
+// the end of the arena are copied into a scratch buffer that is reused between calls.
+// fn must not call methods that write to the same ring.
+func (rr *RecordRing) Range(fn func(rec []byte) bool) {
+	rr.mut.RLock()
+	defer rr.mut.RUnlock()
+
+	var scratch []byte
+	offset := rr.head
+	for i := 0; i < rr.count; i++ {
+		length := rr.length(offset)
+		start := (offset + recordHeaderSize) % len(rr.arena)
+
+		var rec []byte
+		if start+length <= len(rr.arena) {
+			rec = rr.arena[start : start+length : start+length]
+		} else {
+			if cap(scratch) < length {
+				scratch = make([]byte, length)
+			}
+			rec = scratch[:length]
+			n := copy(rec, rr.arena[start:])
+			copy(rec[n:], rr.arena)
+		}
+		if !fn(rec) {
+			return
+		}
+		offset = (start + length) % len(rr.arena)
+	}
+}
+
+// Records returns a copy of every record in "First-In First-Out" (FIFO) order
+func (rr *RecordRing) Records() [][]byte {
+	var result [][]byte
+	rr.Range(func(rec []byte) bool {
+		result = append(result, append([]byte(nil), rec...))
+		return true
+	})
+	return result
+}
+
+// Reset deletes all records within the arena but retains the same exact size
+func (rr *RecordRing) Reset() {
+	rr.mut.Lock()
+	defer rr.mut.Unlock()
+
+	rr.head = 0
+	rr.tail = 0
+	rr.used = 0
+	rr.count = 0
+}
+
+// Length returns the number of records within the arena
+func (rr *RecordRing) Length() int {
+	rr.mut.RLock()
+	defer rr.mut.RUnlock()
+	return rr.count
+}
+
+// Bytes returns the number of bytes used within the arena, including the length prefix
+// of every record
+func (rr *RecordRing) Bytes() int {
+	rr.mut.RLock()
+	defer rr.mut.RUnlock()
+	return rr.used
+}
+
+// Capacity returns the size of the arena in bytes
+func (rr *RecordRing) Capacity() int {
+	return len(rr.arena)
+}
+
+// Overwrites returns the total number of records that were evicted to make room for new
+// records
+func (rr *RecordRing) Overwrites() uint64 {
+	rr.mut.RLock()
+	defer rr.mut.RUnlock()
+	return rr.overwrites
+}
+
+// evict removes the oldest record. It must be called with rr.mut held and at least one
+// record in the arena
+func (rr *RecordRing) evict() {
+	size := recordHeaderSize + rr.length(rr.head)
+	rr.head = (rr.head + size) % len(rr.arena)
+	rr.used -= size
+	rr.count--
+	rr.overwrites++
+
+	// Start over at the beginning of the arena once it is empty, so new records are
+	// less likely to wrap
+	if rr.count == 0 {
+		rr.head = 0
+		rr.tail = 0
+	}
+}
+
+// length reads the length prefix of the record starting at offset, which may itself wrap
+// around the end of the arena
+func (rr *RecordRing) length(offset int) int {
+	var header [recordHeaderSize]byte
+	n := copy(header[:], rr.arena[offset:])
+	copy(header[n:], rr.arena)
+	return int(binary.LittleEndian.Uint32(header[:]))
+}
+
+// copyIn copies p into the arena starting at offset, wrapping around the end of the
+// arena if needed
+func (rr *RecordRing) copyIn(offset int, p []byte) {
+	n := copy(rr.arena[offset:], p)
+	copy(rr.arena, p[n:])
+}
@@ -0,0 +1,89 @@
+package ringbuffer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewRecordRing(t *testing.T) {
+	if _, err := NewRecordRing(4); !errors.Is(err, ErrCapacityNegativeOrZero) {
+		t.Errorf("incorrect error, expected %v but got %v", ErrCapacityNegativeOrZero, err)
+		t.Fail()
+	}
+	rr, _ := NewRecordRing(8)
+	if err := rr.Append([]byte("12345")); !errors.Is(err, ErrRecordTooLarge) {
+		t.Errorf("incorrect error on Append(), expected %v but got %v", ErrRecordTooLarge, err)
+		t.Fail()
+	}
+}
+
+func TestRecordRing(t *testing.T) {
+	rr, _ := NewRecordRing(20)
+	for _, rec := range []string{"alpha", "beta", "gamma"} {
+		if err := rr.Append([]byte(rec)); err != nil {
+			t.Errorf("failed to append record: %s", err)
+			t.Fail()
+		}
+	}
+
+	// 9 + 8 bytes were used, so appending "gamma" evicted "alpha", and the length prefix
+	// of "gamma" wraps around the end of the arena
+	expected := [][]byte{[]byte("beta"), []byte("gamma")}
+	if !reflect.DeepEqual(expected, rr.Records()) {
+		t.Errorf("incorrect result on Records(), expected %q but got %q", expected, rr.Records())
+		t.Fail()
+	}
+	if rr.Length() != 2 || rr.Bytes() != 17 || rr.Overwrites() != 1 {
+		t.Errorf("incorrect state, expected 2 records using 17 bytes with 1 overwrite but got %d using %d with %d",
+			rr.Length(), rr.Bytes(), rr.Overwrites())
+		t.Fail()
+	}
+
+	// Appending a record as large as the arena allows evicts everything else
+	if err := rr.Append([]byte("0123456789abcdef")); err != nil {
+		t.Errorf("failed to append record: %s", err)
+		t.Fail()
+	}
+	if rr.Length() != 1 || string(rr.Records()[0]) != "0123456789abcdef" {
+		t.Errorf("incorrect result on Records(), expected only the largest record but got %q", rr.Records())
+		t.Fail()
+	}
+
+	rr.Reset()
+	if rr.Length() != 0 || rr.Bytes() != 0 {
+		t.Errorf("record ring should be empty after Reset()")
+		t.Fail()
+	}
+}
+
+func TestRecordRingRange(t *testing.T) {
+	rr, _ := NewRecordRing(64)
+	for _, rec := range []string{"a", "", "c"} {
+		_ = rr.Append([]byte(rec))
+	}
+	var result []string
+	rr.Range(func(rec []byte) bool {
+		result = append(result, string(rec))
+		return len(result) < 2
+	})
+	if !reflect.DeepEqual([]string{"a", ""}, result) {
+		t.Errorf("incorrect result on Range(), expected %q but got %q", []string{"a", ""}, result)
+		t.Fail()
+	}
+}
+
+func TestRecordRingWrappedData(t *testing.T) {
+	rr, _ := NewRecordRing(20)
+	for _, rec := range []string{"aaa", "bbb", "cccccccc"} {
+		_ = rr.Append([]byte(rec))
+	}
+
+	// The length prefix of "cccccccc" is stored at offset 14, so the record itself
+	// wraps around the end of the arena and must be read through the scratch buffer
+	expected := [][]byte{[]byte("bbb"), []byte("cccccccc")}
+	if !reflect.DeepEqual(expected, rr.Records()) {
+		t.Errorf("incorrect result on Records(), expected %q but got %q", expected, rr.Records())
+		t.Fail()
+	}
+}
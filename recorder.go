@@ -0,0 +1,183 @@
+package ringbuffer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recorder is a flight recorder: it keeps the last events of an application in memory
+// and dumps them when something goes wrong, on a panic (see DumpOnPanic), on a signal
+// (see DumpOnSignal) or when its HTTP handler is requested.
+//
+// Every event is recorded with its time, and with WithGoroutineIDs also with the ID of
+// the goroutine that recorded it. By default, recording an event costs a single locked
+// write into a RingBuffer, so it is cheap enough to do on hot paths. Looking up the
+// goroutine ID requires formatting a stack trace, which makes every event take several
+// microseconds instead; see BenchmarkRecord.
+type Recorder struct {
+	events     *RingBuffer[string] // The messages, stamped with the write time
+	goroutines bool                // Messages are prefixed with "<goroutine ID> "
+}
+
+// RecorderOption configures a flight recorder created by NewRecorder
+type RecorderOption func(*recorderOptions)
+
+// recorderOptions collects the settings of every RecorderOption passed to NewRecorder
+type recorderOptions struct {
+	goroutines bool
+}
+
+// WithGoroutineIDs records the ID of the goroutine that recorded every event. Looking
+// up the ID costs a few microseconds per event, see Recorder.
+func WithGoroutineIDs() RecorderOption {
+	return func(o *recorderOptions) {
+		o.goroutines = true
+	}
+}
+
+// Event is a single event recorded by a Recorder
+type Event struct {
+	Time      time.Time
+	Goroutine uint64 // Zero unless the recorder was created with WithGoroutineIDs
+	Message   string
+}
+
+// String formats the event as a line of a dump, without the newline
+func (e Event) String() string {
+	if e.Goroutine == 0 {
+		return e.Time.Format(time.RFC3339Nano) + ": " + e.Message
+	}
+	return e.Time.Format(time.RFC3339Nano) + " goroutine " +
+		strconv.FormatUint(e.Goroutine, 10) + ": " + e.Message
+}
+
+// NewRecorder creates a flight recorder that keeps the last events events
+func NewRecorder(events int, opts ...RecorderOption) (*Recorder, error) {
+	var o recorderOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	rb, err := New[string](events, WithClock(time.Now))
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{events: rb, goroutines: o.goroutines}, nil
+}
+
+// Record records an event with the given message
+func (r *Recorder) Record(msg string) {
+	if r.goroutines {
+		msg = strconv.FormatUint(goroutineID(), 10) + " " + msg
+	}
+	r.events.Write(msg)
+}
+
+// Recordf records an event with a message formatted like fmt.Sprintf
+func (r *Recorder) Recordf(format string, args ...any) {
+	r.Record(fmt.Sprintf(format, args...))
+}
+
+// Events returns the recorded events, oldest first
+func (r *Recorder) Events() []Event {
+	snap := r.events.Snapshot()
+	stamps := snap.Timestamps()
+	events := make([]Event, 0, snap.Len())
+	snap.Range(func(i int, entry string) bool {
+		e := Event{Time: stamps[i], Message: entry}
+		if r.goroutines {
+			id, msg, _ := strings.Cut(entry, " ")
+			e.Goroutine, _ = strconv.ParseUint(id, 10, 64)
+			e.Message = msg
+		}
+		events = append(events, e)
+		return true
+	})
+	return events
+}
+
+// Dump writes the recorded events to w, oldest first, one line per event
+func (r *Recorder) Dump(w io.Writer) error {
+	var buf bytes.Buffer
+	for _, e := range r.Events() {
+		buf.WriteString(e.String())
+		buf.WriteByte('\n')
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// DumpFile writes the recorded events to the file at path, replacing it atomically
+func (r *Recorder) DumpFile(path string) error {
+	var buf bytes.Buffer
+	if err := r.Dump(&buf); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// DumpOnPanic dumps the recorded events to w if the goroutine is panicking, then
+// continues panicking. It must be deferred directly, at the top of main or of any
+// goroutine whose panics should be captured:
+//
+//	defer rec.DumpOnPanic(os.Stderr)
+//
+// The panic value itself is recorded as the last event before the dump.
+func (r *Recorder) DumpOnPanic(w io.Writer) {
+	p := recover()
+	if p == nil {
+		return
+	}
+	r.Recordf("panic: %v", p)
+	_ = r.Dump(w)
+	panic(p)
+}
+
+// DumpOnSignal dumps the recorded events to w every time one of the given signals is
+// received, e.g. syscall.SIGUSR1, until the returned stop function is called.
+func (r *Recorder) DumpOnSignal(w io.Writer, sigs ...os.Signal) (stop func()) {
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(c, sigs...)
+	go func() {
+		for {
+			select {
+			case <-c:
+				_ = r.Dump(w)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(c)
+		close(done)
+	}
+}
+
+// ServeHTTP dumps the recorded events as plain text. It implements http.Handler, so a
+// Recorder can be mounted on a debug server, e.g. http.Handle("/debug/events", rec).
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_ = r.Dump(w)
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the first line of
+// its stack trace, "goroutine <ID> [running]:"
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,185 @@
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNewRecorder(t *testing.T) {
+	if _, err := NewRecorder(0); !errors.Is(err, ErrCapacityNegativeOrZero) {
+		t.Errorf("incorrect error, expected %v but got %v", ErrCapacityNegativeOrZero, err)
+		t.Fail()
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	rec, _ := NewRecorder(2, WithGoroutineIDs())
+	before := time.Now()
+	rec.Record("dropped")
+	rec.Record("connected")
+
+	var other uint64
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		other = goroutineID()
+		rec.Recordf("request %d took %s", 7, time.Second)
+	}()
+	wg.Wait()
+
+	events := rec.Events()
+	if len(events) != 2 {
+		t.Fatalf("incorrect number of events, expected 2 but got %d", len(events))
+	}
+	if events[0].Message != "connected" || events[0].Goroutine != goroutineID() ||
+		events[0].Time.Before(before) {
+		t.Errorf("incorrect first event, got %+v", events[0])
+		t.Fail()
+	}
+	if events[1].Message != "request 7 took 1s" || events[1].Goroutine != other ||
+		other == goroutineID() || other == 0 {
+		t.Errorf("incorrect second event from goroutine %d, got %+v", other, events[1])
+		t.Fail()
+	}
+
+	var dump bytes.Buffer
+	rec.Dump(&dump)
+	expected := events[0].String() + "\n" + events[1].String() + "\n"
+	if dump.String() != expected {
+		t.Errorf("incorrect dump, expected %q but got %q", expected, dump.String())
+		t.Fail()
+	}
+
+	path := filepath.Join(t.TempDir(), "events")
+	if err := rec.DumpFile(path); err != nil {
+		t.Fatalf("failed to dump to file: %s", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != expected {
+		t.Errorf("incorrect dump file, expected %q but got %q", expected, data)
+		t.Fail()
+	}
+
+	recorder := httptest.NewRecorder()
+	rec.ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/events", nil))
+	if recorder.Body.String() != expected ||
+		!strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("incorrect HTTP response, expected %q but got %q", expected, recorder.Body.String())
+		t.Fail()
+	}
+}
+
+func TestRecorderWithoutGoroutineIDs(t *testing.T) {
+	rec, _ := NewRecorder(2)
+	rec.Record("request 7 took 1s")
+
+	events := rec.Events()
+	if len(events) != 1 || events[0].Message != "request 7 took 1s" || events[0].Goroutine != 0 {
+		t.Fatalf("incorrect events, expected one event without a goroutine ID but got %+v", events)
+	}
+	expected := events[0].Time.Format(time.RFC3339Nano) + ": request 7 took 1s"
+	if events[0].String() != expected {
+		t.Errorf("incorrect event line, expected %q but got %q", expected, events[0].String())
+		t.Fail()
+	}
+}
+
+func TestRecorderDumpOnPanic(t *testing.T) {
+	rec, _ := NewRecorder(4)
+	var dump bytes.Buffer
+
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Errorf("incorrect re-panic, expected %q but got %v", "boom", p)
+				t.Fail()
+			}
+		}()
+		defer rec.DumpOnPanic(&dump)
+		rec.Record("about to fail")
+		panic("boom")
+	}()
+
+	if !strings.Contains(dump.String(), ": about to fail\n") ||
+		!strings.HasSuffix(dump.String(), ": panic: boom\n") {
+		t.Errorf("incorrect dump, got %q", dump.String())
+		t.Fail()
+	}
+
+	// Nothing is dumped without a panic
+	dump.Reset()
+	func() {
+		defer rec.DumpOnPanic(&dump)
+	}()
+	if dump.Len() != 0 {
+		t.Errorf("nothing should be dumped without a panic, got %q", dump.String())
+		t.Fail()
+	}
+}
+
+// lockedBuffer is a bytes.Buffer that may be read while the recorder dumps into it
+type lockedBuffer struct {
+	mut sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return b.buf.String()
+}
+
+func TestRecorderDumpOnSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals can not be sent to the own process on Windows")
+	}
+	rec, _ := NewRecorder(4)
+	rec.Record("waiting")
+	var dump lockedBuffer
+	stop := rec.DumpOnSignal(&dump, syscall.SIGHUP)
+	defer stop()
+
+	self, _ := os.FindProcess(os.Getpid())
+	if err := self.Signal(syscall.SIGHUP); err != nil {
+		t.Fatalf("failed to send signal: %s", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.HasSuffix(dump.String(), ": waiting\n") {
+		if time.Now().After(deadline) {
+			t.Fatalf("the events were not dumped in time, got %q", dump.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func BenchmarkRecord(b *testing.B) {
+	for _, test := range []struct {
+		name string
+		opts []RecorderOption
+	}{
+		{"Default", nil},
+		{"WithGoroutineIDs", []RecorderOption{WithGoroutineIDs()}},
+	} {
+		b.Run(test.name, func(b *testing.B) {
+			rec, _ := NewRecorder(1024, test.opts...)
+			for i := 0; i < b.N; i++ {
+				rec.Record("event")
+			}
+		})
+	}
+}
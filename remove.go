@@ -0,0 +1,74 @@
+package ringbuffer
+
+// RemoveAt removes the element at logical index i, where index 0 is the oldest element.
+// The remaining elements keep their order. An error is returned if i is out of range.
+func (rb *RingBuffer[T]) RemoveAt(i int) error {
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+
+	if i < 0 || i >= rb.elementCount {
+		return ErrIndexOutOfRange
+	}
+	rb.compact(func(j int, _ T) bool { return j != i })
+	return nil
+}
+
+// RemoveIf removes every element for which pred returns true and returns the number of
+// removed elements. The remaining elements keep their order.
+//
+// pred is called while the buffer is locked, so it must not call methods on the same
+// buffer.
+func (rb *RingBuffer[T]) RemoveIf(pred func(value T) bool) int {
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+	return rb.compact(func(_ int, value T) bool { return !pred(value) })
+}
+
+// Retain keeps only the elements for which pred returns true and returns the number of
+// removed elements. The remaining elements keep their order.
+//
+// pred is called while the buffer is locked, so it must not call methods on the same
+// buffer.
+func (rb *RingBuffer[T]) Retain(pred func(value T) bool) int {
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+	return rb.compact(func(_ int, value T) bool { return pred(value) })
+}
+
+// compact keeps the elements for which keep returns true, moving them towards the
+// front of the buffer in place, across the wrap boundary if needed. The freed slots at
+// the back are cleared and the number of removed elements is returned. It must be
+// called with rb.mut held
+func (rb *RingBuffer[T]) compact(keep func(i int, value T) bool) int {
+	front := rb.index(0)
+	count := rb.elementCount
+	kept := 0
+
+	for i := 0; i < count; i++ {
+		src := (front + i) % rb.capacity
+		if !keep(i, rb.buffer[src]) {
+			rb.release(rb.buffer[src])
+			continue
+		}
+		if kept != i {
+			dst := (front + kept) % rb.capacity
+			rb.detach()
+			rb.buffer[dst] = rb.buffer[src]
+			if rb.stamps != nil {
+				rb.stamps[dst] = rb.stamps[src]
+			}
+		}
+		kept++
+	}
+
+	for i := kept; i < count; i++ {
+		rb.clear((front + i) % rb.capacity)
+	}
+	rb.elementCount = kept
+	rb.writeIndex = (front + kept) % rb.capacity
+	rb.rewind()
+	if kept != count {
+		rb.mutated()
+	}
+	return count - kept
+}
@@ -0,0 +1,90 @@
+package ringbuffer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// newWrapped returns a buffer of capacity 5 holding 1..5, whose oldest element is
+// stored in the middle of the backing array so that the contents wrap around
+func newWrapped(t *testing.T) *RingBuffer[int] {
+	rb, _ := New[int](5)
+	if err := rb.WriteMany([]int{0, 0, 0}); err != nil {
+		t.Errorf("failed to write to buffer: %s", err)
+		t.Fail()
+	}
+	if err := rb.WriteMany([]int{1, 2, 3, 4, 5}); err != nil {
+		t.Errorf("failed to write to buffer: %s", err)
+		t.Fail()
+	}
+	return rb
+}
+
+func TestRemoveAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		index    int
+		expected []int
+		err      error
+	}{
+		{"negative index", -1, []int{1, 2, 3, 4, 5}, ErrIndexOutOfRange},
+		{"index too large", 5, []int{1, 2, 3, 4, 5}, ErrIndexOutOfRange},
+		{"oldest", 0, []int{2, 3, 4, 5}, nil},
+		{"across wrap", 2, []int{1, 2, 4, 5}, nil},
+		{"newest", 4, []int{1, 2, 3, 4}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rb := newWrapped(t)
+			if err := rb.RemoveAt(test.index); !errors.Is(err, test.err) {
+				t.Errorf("incorrect error on RemoveAt(), expected %v but got %v", test.err, err)
+				t.Fail()
+			}
+			if !reflect.DeepEqual(test.expected, rb.Read()) {
+				t.Errorf("incorrect result on Read(), expected %v but got %v", test.expected, rb.Read())
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestRemoveIf(t *testing.T) {
+	rb := newWrapped(t)
+	if removed := rb.RemoveIf(func(value int) bool { return value%2 == 0 }); removed != 2 {
+		t.Errorf("incorrect number of removed elements, expected %d but got %d", 2, removed)
+		t.Fail()
+	}
+	if !reflect.DeepEqual([]int{1, 3, 5}, rb.Read()) {
+		t.Errorf("incorrect result on Read(), expected %v but got %v", []int{1, 3, 5}, rb.Read())
+		t.Fail()
+	}
+
+	// The buffer must keep working as a ring after compaction
+	rb.Write(6)
+	rb.Write(7)
+	rb.Write(8)
+	if !reflect.DeepEqual([]int{3, 5, 6, 7, 8}, rb.Read()) {
+		t.Errorf("incorrect result on Read(), expected %v but got %v", []int{3, 5, 6, 7, 8}, rb.Read())
+		t.Fail()
+	}
+
+	rb.RemoveIf(func(int) bool { return true })
+	if !rb.IsEmpty() {
+		t.Errorf("buffer should be empty after removing every element but IsEmpty() == %v", rb.IsEmpty())
+		t.Fail()
+	}
+}
+
+func TestRetain(t *testing.T) {
+	rb := newWrapped(t)
+	if removed := rb.Retain(func(value int) bool { return value > 3 }); removed != 3 {
+		t.Errorf("incorrect number of removed elements, expected %d but got %d", 3, removed)
+		t.Fail()
+	}
+	if !reflect.DeepEqual([]int{4, 5}, rb.Read()) {
+		t.Errorf("incorrect result on Read(), expected %v but got %v", []int{4, 5}, rb.Read())
+		t.Fail()
+	}
+}
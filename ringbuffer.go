@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 )
 
 // BufferType provides constraints on the types that may be used for a New RingBuffer
@@ -16,72 +17,176 @@ type BufferType interface {
 
 // RingBuffer is effectively a fixed-size container as a data structure. Fields defined
 // in this struct are named in the context as a fixed-size container.
+//
+// Methods that only read the buffer (Read, Length, Capacity, IsFull, IsEmpty, String, ...)
+// take a shared read lock, so any number of readers proceed concurrently and only
+// serialize against writers.
 type RingBuffer[T BufferType] struct {
 	// buffer contains all data, including undefined elements, of which take a default
 	// value to their respective type. Bool defaults to False, int defaults to 0, string
 	// defaults to "", ... etc
 	buffer       []T
-	mut          sync.Mutex // Handles thread safety and concurrency
-	capacity     int        // Total size of the buffer
-	elementCount int        // Number of values stored within the buffer
-	writeIndex   int        // The next index to write into the buffer when Write() is called
+	mut          sync.RWMutex // Handles thread safety and concurrency; readers share the lock
+	capacity     int          // Total size of the buffer
+	elementCount int          // Number of values stored within the buffer
+	writeIndex   int          // The next index to write into the buffer when Write() is called
+	overwrites   uint64       // Number of values overwritten because the buffer was full
+	shared       bool         // Set when buffer is shared with a Snapshot and must be copied before writing
+
+	overflow OverflowPolicy   // What to do when writing into a full buffer
+	now      func() time.Time // Clock used to timestamp writes; nil when timestamps are off
+	stamps   []time.Time      // Write time of every element, laid out like buffer
+	onEvict  func(T)          // Called with every value evicted from the buffer
+
+	budget int         // Maximum total size of all values in bytes; zero when unbounded
+	bytes  int         // Total size of all values currently in the buffer, in bytes
+	sizeOf func(T) int // Size of a single value in bytes; only set with a budget
+
+	mutations uint64          // Number of changes ever made to the contents, used by an Autosaver
+	watchers  []chan struct{} // Signalled after every change, one channel per Autosaver
 }
 
-// Error handling statements
+// Error handling statements. All errors may be compared with errors.Is
 var (
-	errCapacityNegativeOrZero = errors.New("failed to create a new ring buffer! " +
+	ErrCapacityNegativeOrZero = errors.New("failed to create a new ring buffer! " +
 		"Buffer capacity must be greater than zero")
-	errCapacityTooSmall = errors.New("failed to write to buffer! Ring buffer total " +
+	ErrCapacityTooSmall = errors.New("failed to write to buffer! Ring buffer total " +
 		"capacity is too small for all values to be written")
-	errCapacityResizeTooSmall = errors.New("failed to resize buffer! The number of " +
+	ErrCapacityResizeTooSmall = errors.New("failed to resize buffer! The number of " +
 		"elements/values in the existing buffer exceeds the capacity for the new buffer")
-	errDataLengthIsZero = errors.New("failed to write to buffer! The amount of " +
+	ErrDataLengthIsZero = errors.New("failed to write to buffer! The amount of " +
 		"data to write is zero")
+	ErrBufferEmpty = errors.New("failed to read from buffer! There are no " +
+		"elements/values in the buffer")
+	ErrIndexOutOfRange = errors.New("failed to access buffer! The index is out of " +
+		"range of the elements/values in the buffer")
+	ErrNotNumeric = errors.New("failed to compute statistics! The buffer type is " +
+		"not numeric")
+	ErrBufferFull = errors.New("failed to write to buffer! The buffer is full and " +
+		"its overflow policy rejects new values")
 )
 
 // New is effectively a constructor that creates a new ring buffer with a fixed,
-// zero-indexed capacity and specified type constrained by the BufferType interface
-func New[T BufferType](capacity int) (*RingBuffer[T], error) {
+// zero-indexed capacity and specified type constrained by the BufferType interface.
+//
+// The buffer can be configured with any number of options, e.g. WithOverflow or
+// WithClock. All options are validated before the buffer is created.
+func New[T BufferType](capacity int, opts ...Option) (*RingBuffer[T], error) {
 	if capacity <= 0 {
-		return nil, errCapacityNegativeOrZero
+		return nil, ErrCapacityNegativeOrZero
 	}
-	return &RingBuffer[T]{
-		buffer:   make([]T, capacity),
+
+	var o options
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+	if o.powerOfTwo {
+		capacity = nextPowerOfTwo(capacity)
+	}
+
+	rb := &RingBuffer[T]{
 		capacity: capacity,
-	}, nil
+		overflow: o.overflow,
+		now:      o.now,
+	}
+
+	if o.evictHook != nil {
+		hook, ok := o.evictHook.(func(T))
+		if !ok {
+			return nil, ErrEvictHookType
+		}
+		rb.onEvict = hook
+	}
+
+	if o.backing != nil {
+		backing, ok := o.backing.([]T)
+		if !ok {
+			return nil, ErrBackingSliceType
+		}
+		if len(backing) < capacity {
+			return nil, ErrBackingSliceTooSmall
+		}
+		rb.buffer = backing[:capacity]
+	} else {
+		rb.buffer = make([]T, capacity)
+	}
+
+	if rb.now != nil {
+		rb.stamps = make([]time.Time, capacity)
+	}
+
+	if o.budget > 0 {
+		rb.budget = o.budget
+		rb.sizeOf = defaultSizeFunc[T]()
+		if o.sizeFunc != nil {
+			sizeOf, ok := o.sizeFunc.(func(T) int)
+			if !ok {
+				return nil, ErrSizeFuncType
+			}
+			rb.sizeOf = sizeOf
+		}
+	}
+
+	if o.registry != nil {
+		if err := o.registry.Register(o.metricsName, rb); err != nil {
+			return nil, err
+		}
+	}
+	return rb, nil
 }
 
 // NewSize recreates a new ring buffer with a different capacity or size, but with the same
 // data as the old ring buffer. The NEW capacity cannot be smaller than the number of
 // values or elements contained in the OLD buffer.
+//
+// The new buffer keeps the overflow policy, clock, eviction hook and byte budget of the
+// old buffer, but it is not registered with any metrics Registry.
 func (rb *RingBuffer[T]) NewSize(capacity int) (*RingBuffer[T], error) {
-	rb.mut.Lock()
-	defer rb.mut.Unlock()
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
 
 	if capacity <= 0 {
-		return nil, errCapacityNegativeOrZero
+		return nil, ErrCapacityNegativeOrZero
 	}
 
 	if rb.elementCount > capacity {
-		return nil, errCapacityResizeTooSmall
+		return nil, ErrCapacityResizeTooSmall
 	}
 
-	newBuffer := make([]T, capacity)
-	newBuffer = rb.buffer
-
-	return &RingBuffer[T]{
-		buffer:       newBuffer,
+	// The values are copied in logical order, so the new buffer never shares its backing
+	// array with the old buffer and starts out unwrapped
+	newRb := &RingBuffer[T]{
+		buffer:       make([]T, capacity),
 		capacity:     capacity,
 		elementCount: rb.elementCount,
-		writeIndex:   rb.writeIndex,
-	}, nil
+		writeIndex:   rb.elementCount % capacity,
+		overwrites:   rb.overwrites,
+		overflow:     rb.overflow,
+		now:          rb.now,
+		onEvict:      rb.onEvict,
+		budget:       rb.budget,
+		bytes:        rb.bytes,
+		sizeOf:       rb.sizeOf,
+	}
+	if rb.stamps != nil {
+		newRb.stamps = make([]time.Time, capacity)
+	}
+	for i := 0; i < rb.elementCount; i++ {
+		newRb.buffer[i] = rb.buffer[rb.index(i)]
+		if rb.stamps != nil {
+			newRb.stamps[i] = rb.stamps[rb.index(i)]
+		}
+	}
+	return newRb, nil
 }
 
 // String converts the capacity, writeIndex pointer, count of elements, and contents of
 // the ring buffer into a string, then returns that string
 func (rb *RingBuffer[T]) String() string {
-	rb.mut.Lock()
-	defer rb.mut.Unlock()
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
 
 	bufferStr := "capacity=" + strconv.Itoa(rb.capacity) +
 		", writeIndex=" + strconv.Itoa(rb.writeIndex) +
@@ -101,37 +206,74 @@ func (rb *RingBuffer[T]) String() string {
 
 // Read returns the contents of the buffer in "First-In First-Out" (FIFO) order
 func (rb *RingBuffer[T]) Read() (result []T) {
-	rb.mut.Lock()
-	defer rb.mut.Unlock()
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
 
 	result = make([]T, 0, rb.elementCount)
 
 	for i := 0; i < rb.elementCount; i++ {
-		index := (rb.writeIndex + rb.capacity - rb.elementCount + i) % rb.capacity
-		result = append(result, rb.buffer[index])
+		result = append(result, rb.buffer[rb.index(i)])
 	}
 	return result
 }
 
+// index maps a logical index, where 0 is the oldest element, onto the backing array
+func (rb *RingBuffer[T]) index(i int) int {
+	return (rb.writeIndex + rb.capacity - rb.elementCount + i) % rb.capacity
+}
+
 // Write inserts one element into the thread-safe buffer, overwriting the oldest element
-// (without error) if the buffer is full
+// (without error) if the buffer is full.
+//
+// If the buffer was created with WithOverflow(OverflowReject), a value written into a
+// full buffer is discarded instead. Use TryWrite to find out whether it was.
+//
+// If the buffer was created with WithByteBudget, the oldest elements are also evicted
+// until the new value fits within the budget.
 func (rb *RingBuffer[T]) Write(value T) {
+	_ = rb.TryWrite(value)
+}
+
+// TryWrite inserts one element into the thread-safe buffer just like Write, but returns
+// ErrBufferFull if the buffer is full and its overflow policy rejects new values, or
+// ErrElementTooLarge if the value alone exceeds the byte budget of the buffer
+func (rb *RingBuffer[T]) TryWrite(value T) error {
 	rb.mut.Lock()
 	defer rb.mut.Unlock()
+	return rb.write(value)
+}
+
+// write does the work for Write and must be called with rb.mut held
+func (rb *RingBuffer[T]) write(value T) error {
+	if rb.elementCount == rb.capacity && rb.overflow == OverflowReject {
+		return ErrBufferFull
+	}
+	size, err := rb.reserve(value, rb.evictFront)
+	if err != nil {
+		return err
+	}
 
+	// When the buffer is full, the oldest element is stored right at writeIndex, so it
+	// is evicted to make room for the new value
+	if rb.elementCount == rb.capacity {
+		rb.evictFront()
+	}
+
+	rb.detach()
 	rb.buffer[rb.writeIndex] = value
+	if rb.stamps != nil {
+		rb.stamps[rb.writeIndex] = rb.now()
+	}
+	rb.bytes += size
 
 	// rb.writeIndex acts as a logical pointer that moves forward each time Write(...)
 	//	is called.
 	// When writeIndex reaches the buffer capacity, it wraps around to the beginning of
 	//	the buffer by using the modulo operator
 	rb.writeIndex = (rb.writeIndex + 1) % rb.capacity
-
-	// Only increment the elementCount of elements in the buffer when the buffer
-	// isn't full
-	if rb.elementCount < rb.capacity {
-		rb.elementCount++
-	}
+	rb.elementCount++
+	rb.mutated()
+	return nil
 }
 
 // WriteMany first checks if the number of values is greater than the buffer or if the
@@ -139,37 +281,109 @@ func (rb *RingBuffer[T]) Write(value T) {
 // error is returned.
 //
 // Otherwise, WriteMany iterates over each slice of elements or values passed into it and
-// calls Write for each element / value.
+// writes each element / value while holding the lock once. If the buffer rejects new
+// values when full and not all values fit, ErrBufferFull is returned and nothing is
+// written. Likewise, nothing is written if any value alone exceeds the byte budget of
+// the buffer.
 func (rb *RingBuffer[T]) WriteMany(values []T) error {
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+
 	if len(values) > rb.capacity {
-		return errCapacityTooSmall
+		return ErrCapacityTooSmall
 	} else if len(values) == 0 {
-		return errDataLengthIsZero
+		return ErrDataLengthIsZero
+	}
+	if rb.overflow == OverflowReject && len(values) > rb.capacity-rb.elementCount {
+		return ErrBufferFull
+	}
+	if rb.budget > 0 {
+		total := 0
+		for _, val := range values {
+			size := rb.sizeOf(val)
+			if size > rb.budget {
+				return ErrElementTooLarge
+			}
+			total += size
+		}
+		if rb.overflow == OverflowReject && rb.bytes+total > rb.budget {
+			return ErrBufferFull
+		}
 	}
 
 	for _, val := range values {
-		rb.Write(val)
+		_ = rb.write(val)
 	}
 	return nil
 }
 
-// Reset deletes all data within the buffer by re-allocation but retains the same exact
-// capacity
+// Reset deletes all data within the buffer but retains the same exact capacity. The
+// backing array is zeroed in place, so a slice passed to WithBackingSlice stays in use.
 func (rb *RingBuffer[T]) Reset() {
 	rb.mut.Lock()
 	defer rb.mut.Unlock()
 
-	rb.buffer = make([]T, rb.capacity)
+	rb.zero()
+	rb.bytes = 0
 	rb.elementCount = 0 // there's nothing (no elements/values) in the buffer, of course
 	rb.writeIndex = 0   // reset the logical pointer to the beginning of the buffer
+	rb.mutated()
+}
+
+// zero clears every slot of the backing array and the write times in place. If they are
+// shared with a Snapshot, the buffer gets fresh arrays instead, since the snapshot must
+// keep its contents. It must be called with rb.mut held
+func (rb *RingBuffer[T]) zero() {
+	if rb.shared {
+		rb.buffer = make([]T, rb.capacity)
+		if rb.stamps != nil {
+			rb.stamps = make([]time.Time, rb.capacity)
+		}
+		rb.shared = false
+		return
+	}
+
+	var zero T
+	for i := range rb.buffer {
+		rb.buffer[i] = zero
+	}
+	for i := range rb.stamps {
+		rb.stamps[i] = time.Time{}
+	}
+}
+
+// Timestamps returns the time every element was written in "First-In First-Out" (FIFO)
+// order, matching the order of Read(). It returns nil unless the buffer was created
+// with WithClock.
+func (rb *RingBuffer[T]) Timestamps() []time.Time {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+
+	if rb.stamps == nil {
+		return nil
+	}
+	result := make([]time.Time, 0, rb.elementCount)
+	for i := 0; i < rb.elementCount; i++ {
+		result = append(result, rb.stamps[rb.index(i)])
+	}
+	return result
+}
+
+// Overwrites returns the total number of values that were overwritten by Write because
+// the buffer was full. The count is retained across Reset() so it can be exported as a
+// monotonic counter.
+func (rb *RingBuffer[T]) Overwrites() uint64 {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+	return rb.overwrites
 }
 
 // Length returns the number of elements or values within the buffer.
 //
 // For getting the total capacity of the buffer, use Capacity() or Size()
 func (rb *RingBuffer[T]) Length() int {
-	rb.mut.Lock()
-	defer rb.mut.Unlock()
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
 	return rb.elementCount
 }
 
@@ -178,8 +392,8 @@ func (rb *RingBuffer[T]) Length() int {
 //
 // For getting the number of elements in a buffer, use Length()
 func (rb *RingBuffer[T]) Capacity() int {
-	rb.mut.Lock()
-	defer rb.mut.Unlock()
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
 	return rb.capacity
 }
 
@@ -197,15 +411,15 @@ func (rb *RingBuffer[T]) Size() int {
 // IsFull returns a boolean indicating if the number of elements or values of the buffer
 // equals the buffer capacity or size.
 func (rb *RingBuffer[T]) IsFull() bool {
-	rb.mut.Lock()
-	defer rb.mut.Unlock()
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
 	return rb.elementCount == rb.capacity
 }
 
 // IsEmpty returns a boolean indicating if the number of elements or values within the
 // buffer is zero. Additionally, the write index must be zero.
 func (rb *RingBuffer[T]) IsEmpty() bool {
-	rb.mut.Lock()
-	defer rb.mut.Unlock()
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
 	return rb.elementCount == 0 && rb.writeIndex == 0
 }
@@ -3,7 +3,10 @@ package ringbuffer
 import (
 	"errors"
 	"reflect"
+	"runtime"
+	"strconv"
 	"testing"
+	"time"
 )
 
 func TestNew(t *testing.T) {
@@ -25,7 +28,7 @@ func TestNew(t *testing.T) {
 			switch test.expectedType {
 			case "negative capacity":
 				rb, err := New[string](test.capacity)
-				if rb == nil || errors.Is(err, errCapacityNegativeOrZero) {
+				if rb == nil || errors.Is(err, ErrCapacityNegativeOrZero) {
 					t.Log("expected error when creating a zero length buffer, all is ok!")
 				} else {
 					t.Errorf("zero size buffer should be producing an error but incorrectly returns: %s", err)
@@ -33,7 +36,7 @@ func TestNew(t *testing.T) {
 				}
 			case "zero size":
 				rb, err := New[string](test.capacity)
-				if rb == nil || errors.Is(err, errCapacityNegativeOrZero) {
+				if rb == nil || errors.Is(err, ErrCapacityNegativeOrZero) {
 					t.Log("expected error when creating a zero length buffer, all is ok!")
 				} else {
 					t.Errorf("zero size buffer should be producing an error but incorrectly returns: %s", err)
@@ -114,7 +117,7 @@ func TestNewSize(t *testing.T) {
 				}
 
 				rb, err = rb.NewSize(test.capacity)
-				if rb == nil || errors.Is(err, errCapacityNegativeOrZero) {
+				if rb == nil || errors.Is(err, ErrCapacityNegativeOrZero) {
 					t.Log("zero size buffer error expected, all is ok!")
 				} else {
 					t.Errorf("zero length buffer should be producing an error but incorrectly returns: %s", err)
@@ -129,7 +132,7 @@ func TestNewSize(t *testing.T) {
 				}
 
 				rb, err := rb.NewSize(2)
-				if rb == nil || errors.Is(err, errCapacityResizeTooSmall) {
+				if rb == nil || errors.Is(err, ErrCapacityResizeTooSmall) {
 					t.Log("buffer size too small error expected, all is ok!")
 				} else {
 					t.Errorf("buffer size smaller than the number of values should produce an error but incorrectly returns: %s", err)
@@ -157,8 +160,9 @@ func TestNewSize(t *testing.T) {
 					t.Errorf("old buffer element count is different from the new buffer")
 					t.Fail()
 				}
-				if rbOld.writeIndex != rbNew.writeIndex {
-					t.Errorf("old buffer writeIndex is different from the new buffer")
+				if rbNew.writeIndex != rbNew.elementCount%rbNew.capacity {
+					t.Errorf("incorrect writeIndex of the new buffer, expected %d but got %d",
+						rbNew.elementCount%rbNew.capacity, rbNew.writeIndex)
 					t.Fail()
 				}
 			case "resize buffer with no values":
@@ -181,6 +185,43 @@ func TestNewSize(t *testing.T) {
 	}
 }
 
+func TestNewSizeIndependent(t *testing.T) {
+	rbOld, _ := New[int](3, WithClock(time.Now))
+	rbOld.WriteMany([]int{1, 2})
+	rbNew, _ := rbOld.NewSize(5)
+	stamps := rbOld.Timestamps()
+
+	// Writes into either buffer must not show up in the other
+	rbOld.WriteMany([]int{3, 4})
+	rbNew.Write(9)
+	if expected := []int{1, 2, 9}; !reflect.DeepEqual(rbNew.Read(), expected) {
+		t.Errorf("incorrect new buffer, expected %v but got %v", expected, rbNew.Read())
+		t.Fail()
+	}
+	if expected := []int{2, 3, 4}; !reflect.DeepEqual(rbOld.Read(), expected) {
+		t.Errorf("incorrect old buffer, expected %v but got %v", expected, rbOld.Read())
+		t.Fail()
+	}
+	if len(rbNew.Timestamps()) != 3 || !rbNew.Timestamps()[0].Equal(stamps[0]) ||
+		!rbNew.Timestamps()[1].Equal(stamps[1]) {
+		t.Errorf("incorrect timestamps of the new buffer, got %v", rbNew.Timestamps())
+		t.Fail()
+	}
+
+	// Growing a wrapped buffer keeps the values in order and can be filled up
+	rbGrown, err := rbOld.NewSize(5)
+	if err != nil {
+		t.Fatalf("failed to resize: %s", err)
+	}
+	if err := rbGrown.WriteMany([]int{5, 6}); err != nil {
+		t.Fatalf("failed to write to the resized buffer: %s", err)
+	}
+	if expected := []int{2, 3, 4, 5, 6}; !reflect.DeepEqual(rbGrown.Read(), expected) {
+		t.Errorf("incorrect grown buffer, expected %v but got %v", expected, rbGrown.Read())
+		t.Fail()
+	}
+}
+
 func TestRead(t *testing.T) {
 	t.Run("Read()", func(t *testing.T) {
 		rb, _ := New[string](3)
@@ -486,3 +527,99 @@ func TestIsEmpty(t *testing.T) {
 		}
 	})
 }
+
+func TestOverwrites(t *testing.T) {
+	t.Run("Overwrites()", func(t *testing.T) {
+		rb, _ := New[int](2)
+		if err := rb.WriteMany([]int{1, 2}); err != nil {
+			t.Errorf("failed to write to buffer: %s", err)
+			t.Fail()
+		}
+		if rb.Overwrites() != 0 {
+			t.Errorf("incorrect overwrite count, expected %d but got %d", 0, rb.Overwrites())
+			t.Fail()
+		}
+		if err := rb.WriteMany([]int{3, 4}); err != nil {
+			t.Errorf("failed to write to buffer: %s", err)
+			t.Fail()
+		}
+		rb.Reset()
+		if rb.Overwrites() != 2 {
+			t.Errorf("incorrect overwrite count, expected %d but got %d", 2, rb.Overwrites())
+			t.Fail()
+		}
+	})
+}
+
+// benchmarkProcs are the GOMAXPROCS values the concurrency benchmarks are run with
+var benchmarkProcs = []int{1, 2, 4, 8}
+
+func BenchmarkRead(b *testing.B) {
+	rb, _ := New[int](1024)
+	for i := 0; i < 1024; i++ {
+		rb.Write(i)
+	}
+
+	for _, procs := range benchmarkProcs {
+		b.Run("GOMAXPROCS="+strconv.Itoa(procs), func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_ = rb.Read()
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkLength(b *testing.B) {
+	rb, _ := New[int](1024)
+	for i := 0; i < 1024; i++ {
+		rb.Write(i)
+	}
+
+	for _, procs := range benchmarkProcs {
+		b.Run("GOMAXPROCS="+strconv.Itoa(procs), func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_ = rb.Length()
+					_ = rb.IsFull()
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkReadWithWriter(b *testing.B) {
+	rb, _ := New[int](1024)
+	for i := 0; i < 1024; i++ {
+		rb.Write(i)
+	}
+
+	for _, procs := range benchmarkProcs {
+		b.Run("GOMAXPROCS="+strconv.Itoa(procs), func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+
+			// A single background writer keeps mutating the buffer while readers run
+			done := make(chan struct{})
+			go func() {
+				for i := 0; ; i++ {
+					select {
+					case <-done:
+						return
+					default:
+						rb.Write(i)
+					}
+				}
+			}()
+			defer close(done)
+
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_ = rb.Read()
+				}
+			})
+		})
+	}
+}
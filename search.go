@@ -0,0 +1,57 @@
+package ringbuffer
+
+// Contains reports whether value is within the buffer
+func (rb *RingBuffer[T]) Contains(value T) bool {
+	return rb.IndexOf(value) >= 0
+}
+
+// IndexOf returns the logical index of the oldest element equal to value, where index 0
+// is the oldest element, or -1 if value is not within the buffer
+func (rb *RingBuffer[T]) IndexOf(value T) int {
+	return rb.IndexFunc(func(v T) bool { return v == value })
+}
+
+// IndexFunc returns the logical index of the oldest element for which pred returns
+// true, or -1 if there is none.
+//
+// pred is called while the buffer is locked, so it must not call methods on the same
+// buffer.
+func (rb *RingBuffer[T]) IndexFunc(pred func(value T) bool) int {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+
+	for i := 0; i < rb.elementCount; i++ {
+		if pred(rb.buffer[rb.index(i)]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Search uses binary search to find and return the smallest logical index i at which
+// fn returns true, the same way sort.Search does. If fn is false for every element,
+// Search returns Length().
+//
+// Search requires the contents to be sorted with respect to fn in logical order: fn
+// must be false for some (possibly empty) prefix of the elements and true for the rest,
+// regardless of where the elements wrap around in the backing array. For example, on a
+// buffer of increasing timestamps, Search(func(ts int64) bool { return ts >= x }) finds
+// the first element written at or after x.
+//
+// fn is called while the buffer is locked, so it must not call methods on the same
+// buffer.
+func (rb *RingBuffer[T]) Search(fn func(value T) bool) int {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+
+	low, high := 0, rb.elementCount
+	for low < high {
+		mid := int(uint(low+high) >> 1)
+		if !fn(rb.buffer[rb.index(mid)]) {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low
+}
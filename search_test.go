@@ -0,0 +1,60 @@
+package ringbuffer
+
+import "testing"
+
+func TestIndexOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    int
+		expected int
+	}{
+		{"oldest", 1, 0},
+		{"across wrap", 4, 3},
+		{"missing", 6, -1},
+		{"overwritten", 0, -1},
+	}
+	rb := newWrapped(t)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if index := rb.IndexOf(test.value); index != test.expected {
+				t.Errorf("incorrect result on IndexOf(%d), expected %d but got %d", test.value, test.expected, index)
+				t.Fail()
+			}
+			if contains := rb.Contains(test.value); contains != (test.expected >= 0) {
+				t.Errorf("incorrect result on Contains(%d), expected %v but got %v", test.value, test.expected >= 0, contains)
+				t.Fail()
+			}
+		})
+	}
+
+	if index := rb.IndexFunc(func(value int) bool { return value > 2 }); index != 2 {
+		t.Errorf("incorrect result on IndexFunc(), expected %d but got %d", 2, index)
+		t.Fail()
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   int
+		expected int
+	}{
+		{"before all", -5, 0},
+		{"oldest", 1, 0},
+		{"across wrap", 4, 3},
+		{"middle", 3, 2},
+		{"after all", 6, 5},
+	}
+	rb := newWrapped(t)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			index := rb.Search(func(value int) bool { return value >= test.target })
+			if index != test.expected {
+				t.Errorf("incorrect result on Search(>= %d), expected %d but got %d", test.target, test.expected, index)
+				t.Fail()
+			}
+		})
+	}
+}
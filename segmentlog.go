@@ -0,0 +1,435 @@
+package ringbuffer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Error handling statements for SegmentLog
+var (
+	ErrSegmentCountTooSmall = errors.New("failed to open segment log! At least two " +
+		"segments are required")
+	ErrSegmentSizeTooSmall = errors.New("failed to open segment log! The segment " +
+		"size is too small to hold any records")
+	ErrSegmentRecordTooLarge = errors.New("failed to append record! The record does " +
+		"not fit within a single segment")
+	ErrSequenceEvicted = errors.New("failed to read segment log! The record with " +
+		"this sequence number has already been overwritten")
+	ErrSequenceNotFound = errors.New("failed to read segment log! No record has been " +
+		"written with this sequence number yet")
+	ErrCorruptRecord = errors.New("failed to read segment log! The record checksum " +
+		"does not match")
+)
+
+// Layout of segment files. Every segment starts with a header holding the magic and the
+// sequence number of its first record, followed by records made of a length, a CRC-32C
+// checksum of the data, and the data itself. All fields are little-endian
+const (
+	segmentHeaderSize = 16
+	segmentRecordSize = 8 // Length and checksum in front of every record
+)
+
+// segmentMagic identifies a segment file
+var segmentMagic = [4]byte{'R', 'S', 'E', 'G'}
+
+// segmentTable is the CRC-32C table used to checksum records
+var segmentTable = crc32.MakeTable(crc32.Castagnoli)
+
+// SegmentLog is a ring buffer of records on disk, for histories larger than memory. It
+// is made of a fixed number of segment files of a fixed size that are reused in a
+// circle: once the newest segment is full, the oldest segment is truncated and its
+// records are overwritten.
+//
+// Every record is identified by a sequence number, starting at zero, and can be read
+// back in order from any retained sequence number with a SegmentReader. Opening an
+// existing log recovers it after a crash by truncating a torn or corrupt last record.
+type SegmentLog struct {
+	dir         string
+	segmentSize int64
+	mut         sync.RWMutex
+
+	bases   []uint64 // Sequence number of the first record of every segment
+	used    []bool   // Set for every segment that holds a valid header
+	active  int      // Index of the segment records are appended to
+	file    *os.File // The active segment file
+	size    int64    // Size of the active segment file
+	nextSeq uint64   // Sequence number of the next record to append
+}
+
+// OpenSegmentLog opens the segment log in dir, creating the directory and its first
+// segment if needed. The log keeps the given number of segment files of at most
+// segmentSize bytes each, so the disk usage never exceeds segments*segmentSize bytes.
+//
+// The number of segments and their size must not change between runs. Only one
+// SegmentLog, in one process, may have the directory open at a time.
+func OpenSegmentLog(dir string, segments int, segmentSize int64) (*SegmentLog, error) {
+	if segments < 2 {
+		return nil, ErrSegmentCountTooSmall
+	}
+	if segmentSize <= segmentHeaderSize+segmentRecordSize {
+		return nil, ErrSegmentSizeTooSmall
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	sl := &SegmentLog{
+		dir:         dir,
+		segmentSize: segmentSize,
+		bases:       make([]uint64, segments),
+		used:        make([]bool, segments),
+	}
+
+	// The active segment is the one whose first record is the newest
+	found := false
+	for i := range sl.bases {
+		base, err := readSegmentHeader(sl.path(i))
+		if err != nil {
+			continue
+		}
+		sl.bases[i] = base
+		sl.used[i] = true
+		if !found || base > sl.bases[sl.active] {
+			sl.active = i
+			found = true
+		}
+	}
+
+	if !found {
+		if err := sl.startSegment(0, 0); err != nil {
+			return nil, err
+		}
+		return sl, nil
+	}
+	if err := sl.recover(); err != nil {
+		return nil, err
+	}
+	return sl, nil
+}
+
+// readSegmentHeader returns the sequence number of the first record of the segment file
+// at path, or an error if the file does not exist or has no valid header
+func readSegmentHeader(path string) (uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var header [segmentHeaderSize]byte
+	if _, err = io.ReadFull(file, header[:]); err != nil {
+		return 0, err
+	}
+	if [4]byte(header[:4]) != segmentMagic {
+		return 0, ErrCorruptRecord
+	}
+	return binary.LittleEndian.Uint64(header[8:]), nil
+}
+
+// recover scans the active segment for its last valid record, truncates anything after
+// it, and opens the segment for appending
+func (sl *SegmentLog) recover() error {
+	file, err := os.OpenFile(sl.path(sl.active), os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+
+	offset := int64(segmentHeaderSize)
+	count := uint64(0)
+	for {
+		data, err := readRecord(file, offset, sl.segmentSize)
+		if err != nil {
+			break
+		}
+		offset += segmentRecordSize + int64(len(data))
+		count++
+	}
+	if err = file.Truncate(offset); err != nil {
+		file.Close()
+		return err
+	}
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+
+	sl.file = file
+	sl.size = offset
+	sl.nextSeq = sl.bases[sl.active] + count
+	return nil
+}
+
+// readRecord reads the record at offset of a segment file of at most segmentSize bytes
+// and verifies its checksum. A torn record, cut short by a crash, is reported as an
+// error just like a corrupt one
+func readRecord(file *os.File, offset, segmentSize int64) ([]byte, error) {
+	var header [segmentRecordSize]byte
+	if _, err := file.ReadAt(header[:], offset); err != nil {
+		return nil, err
+	}
+	length := int64(binary.LittleEndian.Uint32(header[:4]))
+	if offset+segmentRecordSize+length > segmentSize {
+		return nil, ErrCorruptRecord
+	}
+
+	data := make([]byte, length)
+	if _, err := file.ReadAt(data, offset+segmentRecordSize); err != nil {
+		return nil, err
+	}
+	if crc32.Checksum(data, segmentTable) != binary.LittleEndian.Uint32(header[4:]) {
+		return nil, ErrCorruptRecord
+	}
+	return data, nil
+}
+
+// Append writes rec as a new record and returns its sequence number. If the active
+// segment is full, the oldest segment is reused and its records are overwritten.
+func (sl *SegmentLog) Append(rec []byte) (uint64, error) {
+	size := int64(segmentRecordSize + len(rec))
+	if segmentHeaderSize+size > sl.segmentSize {
+		return 0, ErrSegmentRecordTooLarge
+	}
+
+	sl.mut.Lock()
+	defer sl.mut.Unlock()
+
+	if sl.size+size > sl.segmentSize {
+		if err := sl.startSegment((sl.active+1)%len(sl.bases), sl.nextSeq); err != nil {
+			return 0, err
+		}
+	}
+
+	buf := make([]byte, size)
+	binary.LittleEndian.PutUint32(buf[:4], uint32(len(rec)))
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.Checksum(rec, segmentTable))
+	copy(buf[segmentRecordSize:], rec)
+	if _, err := sl.file.Write(buf); err != nil {
+		sl.rollback()
+		return 0, err
+	}
+
+	seq := sl.nextSeq
+	sl.size += size
+	sl.nextSeq++
+	return seq, nil
+}
+
+// rollback cuts a partially written record off the end of the active segment, so that
+// the next record is appended right after the last complete one. Without it, every
+// later record would sit behind the torn one, where neither readers nor recover can
+// reach it. It must be called with sl.mut held
+func (sl *SegmentLog) rollback() {
+	// Seeking back alone is enough for the next record to overwrite the torn one, so a
+	// failed truncation is not fatal
+	_ = sl.file.Truncate(sl.size)
+	_, _ = sl.file.Seek(sl.size, io.SeekStart)
+}
+
+// startSegment truncates segment i and makes it the active segment, starting with the
+// record with sequence number base. The records of the outgoing segment are synced
+// first, since Sync only flushes the active segment
+func (sl *SegmentLog) startSegment(i int, base uint64) error {
+	if sl.file != nil {
+		if err := sl.file.Sync(); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(sl.path(i), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	var header [segmentHeaderSize]byte
+	copy(header[:], segmentMagic[:])
+	binary.LittleEndian.PutUint64(header[8:], base)
+	if _, err = file.Write(header[:]); err != nil {
+		file.Close()
+		return err
+	}
+	if err = syncDir(sl.dir); err != nil {
+		file.Close()
+		return err
+	}
+
+	if sl.file != nil {
+		sl.file.Close()
+	}
+	sl.file = file
+	sl.size = segmentHeaderSize
+	sl.active = i
+	sl.bases[i] = base
+	sl.used[i] = true
+	return nil
+}
+
+// Oldest returns the sequence number of the oldest record still retained by the log. It
+// equals Next() if the log is empty.
+func (sl *SegmentLog) Oldest() uint64 {
+	sl.mut.RLock()
+	defer sl.mut.RUnlock()
+	return sl.oldest()
+}
+
+// oldest does the work for Oldest and must be called with sl.mut held
+func (sl *SegmentLog) oldest() uint64 {
+	oldest := sl.nextSeq
+	for i, base := range sl.bases {
+		if sl.used[i] && base < oldest {
+			oldest = base
+		}
+	}
+	return oldest
+}
+
+// Next returns the sequence number the next appended record will get
+func (sl *SegmentLog) Next() uint64 {
+	sl.mut.RLock()
+	defer sl.mut.RUnlock()
+	return sl.nextSeq
+}
+
+// Sync flushes the active segment to stable storage. Older segments were already
+// flushed when the log moved on from them, so every appended record is then durable
+func (sl *SegmentLog) Sync() error {
+	sl.mut.RLock()
+	defer sl.mut.RUnlock()
+	return sl.file.Sync()
+}
+
+// Close closes the active segment file. The log must not be used afterwards.
+func (sl *SegmentLog) Close() error {
+	sl.mut.Lock()
+	defer sl.mut.Unlock()
+	return sl.file.Close()
+}
+
+// path returns the path of segment file i
+func (sl *SegmentLog) path(i int) string {
+	return filepath.Join(sl.dir, fmt.Sprintf("segment-%04d.log", i))
+}
+
+// segmentOf returns the index of the segment holding the record with sequence number
+// seq, which is the segment with the newest first record at or before seq. It must be
+// called with sl.mut held and a retained seq
+func (sl *SegmentLog) segmentOf(seq uint64) int {
+	segment := sl.active
+	for i, base := range sl.bases {
+		if sl.used[i] && base <= seq && (sl.bases[segment] > seq || base > sl.bases[segment]) {
+			segment = i
+		}
+	}
+	return segment
+}
+
+// end returns the sequence number following the last record of segment i. It must be
+// called with sl.mut held
+func (sl *SegmentLog) end(i int) uint64 {
+	if i == sl.active {
+		return sl.nextSeq
+	}
+	return sl.bases[(i+1)%len(sl.bases)]
+}
+
+// SegmentReader reads the records of a SegmentLog in order, starting at a given sequence
+// number. It is safe to keep reading while records are appended; once the reader
+// catches up it returns io.EOF until more records are appended.
+type SegmentReader struct {
+	log     *SegmentLog
+	segment int      // Index of the segment being read
+	base    uint64   // Base of the segment when the reader opened it
+	file    *os.File // The segment file being read
+	offset  int64    // Offset of the next record in the segment file
+	seq     uint64   // Sequence number of the next record
+}
+
+// NewReader returns a reader positioned at the record with sequence number seq. An error
+// is returned if that record has already been overwritten, or if seq is past Next().
+func (sl *SegmentLog) NewReader(seq uint64) (*SegmentReader, error) {
+	sl.mut.RLock()
+	defer sl.mut.RUnlock()
+
+	if seq < sl.oldest() {
+		return nil, ErrSequenceEvicted
+	}
+	if seq > sl.nextSeq {
+		return nil, ErrSequenceNotFound
+	}
+
+	r := &SegmentReader{log: sl}
+	if err := r.open(sl.segmentOf(seq)); err != nil {
+		return nil, err
+	}
+	// Skip the records in front of seq within its segment
+	for r.seq < seq {
+		data, err := readRecord(r.file, r.offset, sl.segmentSize)
+		if err != nil {
+			r.file.Close()
+			return nil, err
+		}
+		r.offset += segmentRecordSize + int64(len(data))
+		r.seq++
+	}
+	return r, nil
+}
+
+// open positions the reader at the first record of segment i. It must be called with
+// r.log.mut held
+func (r *SegmentReader) open(i int) error {
+	file, err := os.Open(r.log.path(i))
+	if err != nil {
+		return err
+	}
+	if r.file != nil {
+		r.file.Close()
+	}
+	r.file = file
+	r.segment = i
+	r.base = r.log.bases[i]
+	r.offset = segmentHeaderSize
+	r.seq = r.base
+	return nil
+}
+
+// Next returns the next record and advances the reader. It returns io.EOF once every
+// appended record has been read, and ErrSequenceEvicted if the writer has overwritten
+// the segment being read.
+func (r *SegmentReader) Next() ([]byte, error) {
+	r.log.mut.RLock()
+	defer r.log.mut.RUnlock()
+
+	if r.seq >= r.log.nextSeq {
+		return nil, io.EOF
+	}
+	if r.log.bases[r.segment] != r.base || r.seq < r.log.oldest() {
+		return nil, ErrSequenceEvicted
+	}
+	if r.seq >= r.log.end(r.segment) {
+		if err := r.open((r.segment + 1) % len(r.log.bases)); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := readRecord(r.file, r.offset, r.log.segmentSize)
+	if err != nil {
+		return nil, err
+	}
+	r.offset += segmentRecordSize + int64(len(data))
+	r.seq++
+	return data, nil
+}
+
+// Seq returns the sequence number of the record the next call to Next returns
+func (r *SegmentReader) Seq() uint64 {
+	return r.seq
+}
+
+// Close closes the segment file held by the reader
+func (r *SegmentReader) Close() error {
+	return r.file.Close()
+}
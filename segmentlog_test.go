@@ -0,0 +1,187 @@
+package ringbuffer
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestOpenSegmentLogErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		segments    int
+		segmentSize int64
+		expected    error
+	}{
+		{"one segment", 1, 1024, ErrSegmentCountTooSmall},
+		{"tiny segments", 2, segmentHeaderSize + segmentRecordSize, ErrSegmentSizeTooSmall},
+		{"valid", 2, 1024, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sl, err := OpenSegmentLog(t.TempDir(), test.segments, test.segmentSize)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("incorrect error, expected %v but got %v", test.expected, err)
+				t.Fail()
+			}
+			if err == nil {
+				sl.Close()
+			}
+		})
+	}
+}
+
+// readAll reads every record from seq until io.EOF
+func readAll(t *testing.T, sl *SegmentLog, seq uint64) []string {
+	r, err := sl.NewReader(seq)
+	if err != nil {
+		t.Fatalf("failed to create reader at %d: %s", seq, err)
+	}
+	defer r.Close()
+
+	var result []string
+	for {
+		rec, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return result
+		}
+		if err != nil {
+			t.Fatalf("failed to read record %d: %s", r.Seq(), err)
+		}
+		result = append(result, string(rec))
+	}
+}
+
+func TestSegmentLog(t *testing.T) {
+	dir := t.TempDir()
+
+	// Every record "rec-N" takes 8+5 bytes, so each 16+3*13 byte segment holds three
+	sl, err := OpenSegmentLog(dir, 3, segmentHeaderSize+3*13)
+	if err != nil {
+		t.Fatalf("failed to open segment log: %s", err)
+	}
+	for i := 0; i < 10; i++ {
+		seq, err := sl.Append([]byte("rec-" + strconv.Itoa(i)))
+		if err != nil || seq != uint64(i) {
+			t.Errorf("incorrect result on Append(), expected sequence %d but got %d (%v)", i, seq, err)
+			t.Fail()
+		}
+	}
+
+	// Segments hold 9..9, 3..5 and 6..8 after reusing the first segment
+	if sl.Oldest() != 3 || sl.Next() != 10 {
+		t.Errorf("incorrect retention, expected records 3 to 9 but got %d to %d", sl.Oldest(), sl.Next()-1)
+		t.Fail()
+	}
+	if _, err = sl.NewReader(2); !errors.Is(err, ErrSequenceEvicted) {
+		t.Errorf("incorrect error on NewReader(2), expected %v but got %v", ErrSequenceEvicted, err)
+		t.Fail()
+	}
+	if _, err = sl.NewReader(11); !errors.Is(err, ErrSequenceNotFound) {
+		t.Errorf("incorrect error on NewReader(11), expected %v but got %v", ErrSequenceNotFound, err)
+		t.Fail()
+	}
+
+	records := readAll(t, sl, 4)
+	expected := []string{"rec-4", "rec-5", "rec-6", "rec-7", "rec-8", "rec-9"}
+	if !reflect.DeepEqual(expected, records) {
+		t.Errorf("incorrect records, expected %v but got %v", expected, records)
+		t.Fail()
+	}
+	if err = sl.Close(); err != nil {
+		t.Errorf("an error was not expected on Close(): %s", err)
+		t.Fail()
+	}
+
+	// Simulate a crash in the middle of appending a record to the active segment
+	active := filepath.Join(dir, "segment-0000.log")
+	file, _ := os.OpenFile(active, os.O_WRONLY|os.O_APPEND, 0)
+	_, _ = file.Write([]byte{5, 0, 0, 0, 1, 2})
+	file.Close()
+
+	sl, err = OpenSegmentLog(dir, 3, segmentHeaderSize+3*13)
+	if err != nil {
+		t.Fatalf("failed to reopen segment log: %s", err)
+	}
+	defer sl.Close()
+	if sl.Next() != 10 {
+		t.Errorf("torn record should be truncated, expected next sequence %d but got %d", 10, sl.Next())
+		t.Fail()
+	}
+	if seq, err := sl.Append([]byte("rec-A")); err != nil || seq != 10 {
+		t.Errorf("incorrect result on Append() after recovery, expected sequence %d but got %d (%v)", 10, seq, err)
+		t.Fail()
+	}
+	records = readAll(t, sl, 8)
+	expected = []string{"rec-8", "rec-9", "rec-A"}
+	if !reflect.DeepEqual(expected, records) {
+		t.Errorf("incorrect records after recovery, expected %v but got %v", expected, records)
+		t.Fail()
+	}
+}
+
+func TestSegmentReaderEvicted(t *testing.T) {
+	sl, _ := OpenSegmentLog(t.TempDir(), 2, segmentHeaderSize+2*13)
+	defer sl.Close()
+	for i := 0; i < 2; i++ {
+		_, _ = sl.Append([]byte("rec-" + strconv.Itoa(i)))
+	}
+	r, _ := sl.NewReader(0)
+	defer r.Close()
+
+	// Fill the second segment and reuse the first one underneath the reader
+	for i := 2; i < 5; i++ {
+		_, _ = sl.Append([]byte("rec-" + strconv.Itoa(i)))
+	}
+	if _, err := r.Next(); !errors.Is(err, ErrSequenceEvicted) {
+		t.Errorf("incorrect error on Next(), expected %v but got %v", ErrSequenceEvicted, err)
+		t.Fail()
+	}
+	if _, err := sl.Append(make([]byte, 100)); !errors.Is(err, ErrSegmentRecordTooLarge) {
+		t.Errorf("incorrect error on Append(), expected %v but got %v", ErrSegmentRecordTooLarge, err)
+		t.Fail()
+	}
+}
+
+func TestSegmentLogFailedAppend(t *testing.T) {
+	dir := t.TempDir()
+	sl, err := OpenSegmentLog(dir, 2, segmentHeaderSize+3*13)
+	if err != nil {
+		t.Fatalf("failed to open segment log: %s", err)
+	}
+	_, _ = sl.Append([]byte("rec-0"))
+
+	// Simulate a write that fails partway, e.g. on a full disk, leaving part of the
+	// record behind and the file offset past it
+	_, _ = sl.file.Write([]byte{5, 0, 0, 0, 1, 2})
+	sl.rollback()
+
+	if seq, err := sl.Append([]byte("rec-1")); err != nil || seq != 1 {
+		t.Errorf("incorrect result on Append() after a failed write, expected sequence %d but got %d (%v)", 1, seq, err)
+		t.Fail()
+	}
+	sl.Close()
+
+	// Records appended after the failed write must survive reopening, so that their
+	// sequence numbers are never handed out again
+	sl, err = OpenSegmentLog(dir, 2, segmentHeaderSize+3*13)
+	if err != nil {
+		t.Fatalf("failed to reopen segment log: %s", err)
+	}
+	defer sl.Close()
+	if sl.Next() != 2 {
+		t.Errorf("incorrect next sequence after reopening, expected %d but got %d", 2, sl.Next())
+		t.Fail()
+	}
+	records := readAll(t, sl, 0)
+	expected := []string{"rec-0", "rec-1"}
+	if !reflect.DeepEqual(expected, records) {
+		t.Errorf("incorrect records after a failed write, expected %v but got %v", expected, records)
+		t.Fail()
+	}
+}
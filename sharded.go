@@ -0,0 +1,214 @@
+package ringbuffer
+
+import (
+	"errors"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Error handling statements for Sharded
+var (
+	ErrShardCountNegativeOrZero = errors.New("failed to create a new sharded ring " +
+		"buffer! The number of shards must be greater than zero")
+)
+
+// Sharded distributes writes across several internal ring buffers (shards) so that
+// concurrent writers do not all contend on a single mutex.
+//
+// Every element is tagged with a global sequence number when it is written, which lets
+// Read merge the shards back into the exact global write order. Each shard evicts its
+// own oldest elements independently, so the merged window holds the newest
+// capacityPerShard elements of every shard rather than the newest elements overall.
+//
+// Handing out global sequence numbers still makes every write touch one shared
+// counter. With WithApproximateOrder, writes share no state at all and Read merges the
+// shards by write time instead.
+type Sharded[T BufferType] struct {
+	shards      []shard[T]
+	seq         paddedUint64 // The last sequence number handed out to a write
+	next        paddedUint64 // Round-robin counter used to pick a shard for Write
+	approximate bool         // Set by WithApproximateOrder
+	start       time.Time    // Write times are measured from start on the monotonic clock
+}
+
+// paddedUint64 is an atomic counter that fills a whole cache line, so that writers
+// updating one counter do not slow down writers updating the counter next to it
+type paddedUint64 struct {
+	atomic.Uint64
+	_ [56]byte
+}
+
+// shard pairs the values with their sequence numbers. Both rings are only written while
+// holding mut so they never drift out of sync. The padding keeps the mutexes of
+// neighbouring shards on different cache lines
+type shard[T BufferType] struct {
+	mut    sync.Mutex
+	values *RingBuffer[T]
+	seqs   *RingBuffer[uint64]
+	_      [40]byte
+}
+
+// ShardedOption configures a sharded ring buffer created by NewSharded
+type ShardedOption func(*shardedOptions)
+
+// shardedOptions collects the settings of every ShardedOption passed to NewSharded
+type shardedOptions struct {
+	approximate bool
+}
+
+// WithApproximateOrder trades the exact global order of Read for writes that share no
+// state between shards. Write picks a random shard instead of the next one in
+// round-robin order, and every element is tagged with its write time instead of a
+// global sequence number, so Read may reorder elements written by different shards at
+// almost the same time.
+func WithApproximateOrder() ShardedOption {
+	return func(o *shardedOptions) {
+		o.approximate = true
+	}
+}
+
+// NewSharded creates a sharded ring buffer made of the given number of shards, each
+// with a fixed, zero-indexed capacity of capacityPerShard
+func NewSharded[T BufferType](shards, capacityPerShard int, opts ...ShardedOption) (*Sharded[T], error) {
+	if shards <= 0 {
+		return nil, ErrShardCountNegativeOrZero
+	}
+	var o shardedOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := &Sharded[T]{
+		shards:      make([]shard[T], shards),
+		approximate: o.approximate,
+		start:       time.Now(),
+	}
+	for i := range s.shards {
+		values, err := New[T](capacityPerShard)
+		if err != nil {
+			return nil, err
+		}
+		seqs, err := New[uint64](capacityPerShard)
+		if err != nil {
+			return nil, err
+		}
+		s.shards[i].values = values
+		s.shards[i].seqs = seqs
+	}
+	return s, nil
+}
+
+// Write inserts one element into the next shard in round-robin order (or a random shard
+// with WithApproximateOrder), overwriting the oldest element of that shard (without
+// error) if it is full
+func (s *Sharded[T]) Write(value T) {
+	if s.approximate {
+		s.writeShard(rand.Intn(len(s.shards)), value)
+		return
+	}
+	s.writeShard(int(s.next.Add(1)%uint64(len(s.shards))), value)
+}
+
+// WriteKey inserts one element into the shard selected by hashing key, so that all
+// elements written with the same key land in the same shard
+func (s *Sharded[T]) WriteKey(key string, value T) {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	s.writeShard(int(h.Sum32()%uint32(len(s.shards))), value)
+}
+
+// writeShard tags the value with the next sequence number, or its write time with
+// WithApproximateOrder, and writes it into shard i. The tag is taken while holding the
+// shard lock so that each shard stores its tags in increasing order
+func (s *Sharded[T]) writeShard(i int, value T) {
+	sh := &s.shards[i]
+	sh.mut.Lock()
+	defer sh.mut.Unlock()
+
+	if s.approximate {
+		sh.seqs.Write(uint64(time.Since(s.start)))
+	} else {
+		sh.seqs.Write(s.seq.Add(1))
+	}
+	sh.values.Write(value)
+}
+
+// Read returns the contents of every shard merged in exact global write order, using
+// the sequence number each element was tagged with. With WithApproximateOrder, the
+// shards are merged by write time instead.
+//
+// All shards are locked while they are read, so the result is a consistent view across
+// the whole sharded buffer.
+func (s *Sharded[T]) Read() []T {
+	type entry struct {
+		seq   uint64
+		value T
+	}
+
+	for i := range s.shards {
+		s.shards[i].mut.Lock()
+	}
+	var entries []entry
+	for i := range s.shards {
+		seqs := s.shards[i].seqs.Read()
+		values := s.shards[i].values.Read()
+		for j := range values {
+			entries = append(entries, entry{seq: seqs[j], value: values[j]})
+		}
+	}
+	for i := range s.shards {
+		s.shards[i].mut.Unlock()
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool { return entries[a].seq < entries[b].seq })
+
+	result := make([]T, len(entries))
+	for i := range entries {
+		result[i] = entries[i].value
+	}
+	return result
+}
+
+// Shards returns the number of internal ring buffers
+func (s *Sharded[T]) Shards() int {
+	return len(s.shards)
+}
+
+// Length returns the number of elements or values within all shards combined.
+//
+// For getting the total capacity of all shards, use Capacity()
+func (s *Sharded[T]) Length() int {
+	length := 0
+	for i := range s.shards {
+		length += s.shards[i].values.Length()
+	}
+	return length
+}
+
+// Capacity returns the combined capacity of all shards, as opposed to the number of
+// elements within them.
+//
+// For getting the number of elements in all shards, use Length()
+func (s *Sharded[T]) Capacity() int {
+	capacity := 0
+	for i := range s.shards {
+		capacity += s.shards[i].values.Capacity()
+	}
+	return capacity
+}
+
+// Reset deletes all data within every shard but retains the same exact capacity.
+// Sequence numbers keep increasing across a Reset
+func (s *Sharded[T]) Reset() {
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mut.Lock()
+		sh.values.Reset()
+		sh.seqs.Reset()
+		sh.mut.Unlock()
+	}
+}
@@ -0,0 +1,161 @@
+package ringbuffer
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestNewSharded(t *testing.T) {
+	tests := []struct {
+		name     string
+		shards   int
+		capacity int
+		expected error
+	}{
+		{"zero shards", 0, 3, ErrShardCountNegativeOrZero},
+		{"zero capacity", 2, 0, ErrCapacityNegativeOrZero},
+		{"valid", 4, 3, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := NewSharded[int](test.shards, test.capacity)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("incorrect error, expected %v but got %v", test.expected, err)
+				t.Fail()
+			}
+			if err == nil && (s.Shards() != test.shards || s.Capacity() != test.shards*test.capacity) {
+				t.Errorf("incorrect shape, expected %d shards of %d but got %d shards totalling %d",
+					test.shards, test.capacity, s.Shards(), s.Capacity())
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestShardedRead(t *testing.T) {
+	t.Run("global order", func(t *testing.T) {
+		s, _ := NewSharded[int](3, 4)
+		expected := []int{}
+		for i := 0; i < 10; i++ {
+			s.Write(i)
+			expected = append(expected, i)
+		}
+		if !reflect.DeepEqual(expected, s.Read()) {
+			t.Errorf("incorrect result on Read(), expected %v but got %v", expected, s.Read())
+			t.Fail()
+		}
+	})
+	t.Run("keyed eviction", func(t *testing.T) {
+		s, _ := NewSharded[string](2, 2)
+		for _, value := range []string{"a", "b", "c"} {
+			s.WriteKey("user", value)
+		}
+		expected := []string{"b", "c"}
+		if !reflect.DeepEqual(expected, s.Read()) {
+			t.Errorf("incorrect result on Read(), expected %v but got %v", expected, s.Read())
+			t.Fail()
+		}
+		s.Reset()
+		if s.Length() != 0 {
+			t.Errorf("incorrect length after Reset(), expected %d but got %d", 0, s.Length())
+			t.Fail()
+		}
+	})
+	t.Run("concurrent writers", func(t *testing.T) {
+		s, _ := NewSharded[int](4, 100)
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					s.Write(i)
+				}
+			}()
+		}
+		wg.Wait()
+		if s.Length() != 200 {
+			t.Errorf("incorrect length, expected %d but got %d", 200, s.Length())
+			t.Fail()
+		}
+	})
+}
+
+func TestShardedApproximateOrder(t *testing.T) {
+	s, _ := NewSharded[int](4, 100, WithApproximateOrder())
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				s.Write(w*50 + i)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	result := s.Read()
+	sort.Ints(result)
+	for i, value := range result {
+		if value != i {
+			t.Fatalf("incorrect result on Read(), expected every value from 0 to 199 but got %v", result)
+		}
+	}
+	if len(result) != 200 {
+		t.Errorf("incorrect length, expected %d but got %d", 200, len(result))
+		t.Fail()
+	}
+}
+
+func TestShardedPadding(t *testing.T) {
+	if size := unsafe.Sizeof(shard[int]{}); size%64 != 0 {
+		t.Errorf("incorrect shard size, expected a multiple of 64 but got %d", size)
+		t.Fail()
+	}
+	if size := unsafe.Sizeof(paddedUint64{}); size != 64 {
+		t.Errorf("incorrect counter size, expected 64 but got %d", size)
+		t.Fail()
+	}
+}
+
+// BenchmarkShardedWrite compares parallel writes into a single RingBuffer with writes
+// into a Sharded buffer of the same total capacity, in exact and approximate order
+func BenchmarkShardedWrite(b *testing.B) {
+	const shards, capacity = 8, 1024
+	writers := map[string]func() func(int){
+		"RingBuffer": func() func(int) {
+			rb, _ := New[int](shards * capacity)
+			return rb.Write
+		},
+		"Sharded": func() func(int) {
+			s, _ := NewSharded[int](shards, capacity)
+			return s.Write
+		},
+		"ShardedApproximate": func() func(int) {
+			s, _ := NewSharded[int](shards, capacity, WithApproximateOrder())
+			return s.Write
+		},
+	}
+
+	for _, name := range []string{"RingBuffer", "Sharded", "ShardedApproximate"} {
+		for _, procs := range benchmarkProcs {
+			b.Run(name+"/GOMAXPROCS="+strconv.Itoa(procs), func(b *testing.B) {
+				defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+				write := writers[name]()
+				b.RunParallel(func(pb *testing.PB) {
+					for i := 0; pb.Next(); i++ {
+						write(i)
+					}
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package ringbuffer
+
+import (
+	"encoding/binary"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+// shmMagic identifies a shared-memory ring buffer
+var shmMagic = [8]byte{'R', 'I', 'N', 'G', 'S', 'H', 'M', 0}
+
+// Layout of the shared-memory header. The head and tail positions each get their own
+// cache line, so that the producer and consumer do not false-share
+const (
+	shmVersion    = 1
+	shmOffsetHead = 64
+	shmOffsetTail = 128
+	shmHeaderSize = 192
+)
+
+// ShmRing is a single-producer, single-consumer (SPSC) ring buffer in a shared-memory
+// file, e.g. under /dev/shm, for exchanging values between two processes on the same
+// host. One process writes with Write, the other reads with Pop or ReadMany, and
+// neither side makes a syscall or takes a lock on the hot path: the head and tail
+// positions are updated atomically in the mapped header.
+//
+// Unlike RingBuffer, a ShmRing never overwrites values the consumer has not read yet;
+// Write returns ErrBufferFull instead. The header and slot layout are in native byte
+// order, so both processes must run on the same machine.
+type ShmRing[T FixedType] struct {
+	file     *os.File
+	data     []byte         // The whole mapped file
+	slots    []T            // The slot array, pointing into data
+	head     *atomic.Uint64 // Total number of values read, only advanced by the consumer
+	tail     *atomic.Uint64 // Total number of values written, only advanced by the producer
+	capacity uint64
+}
+
+// CreateShmRing creates the shared-memory ring buffer at path with a fixed capacity,
+// replacing any existing file. The other process then opens it with OpenShmRing.
+//
+// An existing file is removed rather than truncated, so a process that still has it
+// mapped keeps working on the old file instead of crashing with SIGBUS.
+func CreateShmRing[T FixedType](path string, capacity int) (*ShmRing[T], error) {
+	if capacity <= 0 {
+		return nil, ErrCapacityNegativeOrZero
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	var zero T
+	size := shmHeaderSize + capacity*int(unsafe.Sizeof(zero))
+	if err = file.Truncate(int64(size)); err != nil {
+		file.Close()
+		return nil, err
+	}
+	sr, err := mapShmRing[T](file, size)
+	if err != nil {
+		return nil, err
+	}
+
+	header := sr.data[:shmHeaderSize]
+	binary.LittleEndian.PutUint32(header[offsetVersion:], shmVersion)
+	binary.LittleEndian.PutUint32(header[offsetTypeTag:], typeTag[T]())
+	binary.LittleEndian.PutUint32(header[offsetElementSize:], uint32(unsafe.Sizeof(zero)))
+	binary.LittleEndian.PutUint64(header[offsetCapacity:], uint64(capacity))
+	// Write the magic last, so a concurrent OpenShmRing never sees a partial header
+	copy(header, shmMagic[:])
+
+	sr.init(capacity)
+	return sr, nil
+}
+
+// OpenShmRing opens an existing shared-memory ring buffer created by CreateShmRing. The
+// capacity is read from the file, and the buffer type must match the one it was
+// created with.
+func OpenShmRing[T FixedType](path string) (*ShmRing[T], error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.Size() < shmHeaderSize {
+		file.Close()
+		return nil, ErrFileCorrupt
+	}
+	sr, err := mapShmRing[T](file, int(info.Size()))
+	if err != nil {
+		return nil, err
+	}
+
+	var zero T
+	header := sr.data[:shmHeaderSize]
+	capacity := binary.LittleEndian.Uint64(header[offsetCapacity:])
+	switch {
+	case [8]byte(header[:8]) != shmMagic,
+		binary.LittleEndian.Uint32(header[offsetVersion:]) != shmVersion:
+		sr.Close()
+		return nil, ErrFileCorrupt
+	case binary.LittleEndian.Uint32(header[offsetTypeTag:]) != typeTag[T](),
+		binary.LittleEndian.Uint32(header[offsetElementSize:]) != uint32(unsafe.Sizeof(zero)):
+		sr.Close()
+		return nil, ErrFileTypeMismatch
+	case capacity == 0 || uint64(info.Size()) != shmHeaderSize+capacity*uint64(unsafe.Sizeof(zero)):
+		sr.Close()
+		return nil, ErrFileCorrupt
+	}
+
+	sr.init(int(capacity))
+	return sr, nil
+}
+
+// mapShmRing maps size bytes of file into memory, closing the file on failure
+func mapShmRing[T FixedType](file *os.File, size int) (*ShmRing[T], error) {
+	data, err := syscall.Mmap(int(file.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &ShmRing[T]{file: file, data: data}, nil
+}
+
+// init points the positions and slots into the mapped header
+func (sr *ShmRing[T]) init(capacity int) {
+	sr.capacity = uint64(capacity)
+	sr.head = (*atomic.Uint64)(unsafe.Pointer(&sr.data[shmOffsetHead]))
+	sr.tail = (*atomic.Uint64)(unsafe.Pointer(&sr.data[shmOffsetTail]))
+	sr.slots = unsafe.Slice((*T)(unsafe.Pointer(&sr.data[shmHeaderSize])), capacity)
+}
+
+// Write inserts one element into the buffer. It returns ErrBufferFull if the consumer
+// has not read enough values to make room. Only the producer may call Write.
+func (sr *ShmRing[T]) Write(value T) error {
+	tail := sr.tail.Load()
+	if tail-sr.head.Load() == sr.capacity {
+		return ErrBufferFull
+	}
+	sr.slots[tail%sr.capacity] = value
+	// Publishing the new tail makes the value visible to the consumer
+	sr.tail.Store(tail + 1)
+	return nil
+}
+
+// Pop removes and returns the oldest element of the buffer. It returns ErrBufferEmpty
+// if there is nothing to read. Only the consumer may call Pop.
+func (sr *ShmRing[T]) Pop() (T, error) {
+	head := sr.head.Load()
+	if head == sr.tail.Load() {
+		var zero T
+		return zero, ErrBufferEmpty
+	}
+	value := sr.slots[head%sr.capacity]
+	// Publishing the new head hands the slot back to the producer
+	sr.head.Store(head + 1)
+	return value, nil
+}
+
+// ReadMany removes up to len(dst) of the oldest elements, copies them into dst in
+// "First-In First-Out" (FIFO) order and returns how many were copied. Only the consumer
+// may call ReadMany.
+func (sr *ShmRing[T]) ReadMany(dst []T) int {
+	head := sr.head.Load()
+	n := sr.tail.Load() - head
+	if uint64(len(dst)) < n {
+		n = uint64(len(dst))
+	}
+	for i := uint64(0); i < n; i++ {
+		dst[i] = sr.slots[(head+i)%sr.capacity]
+	}
+	sr.head.Store(head + n)
+	return int(n)
+}
+
+// Length returns the number of elements written but not read yet
+func (sr *ShmRing[T]) Length() int {
+	return int(sr.tail.Load() - sr.head.Load())
+}
+
+// Capacity returns the zero-indexed capacity of the ring buffer itself, as opposed to the
+// number of elements within the buffer
+func (sr *ShmRing[T]) Capacity() int {
+	return int(sr.capacity)
+}
+
+// Close unmaps and closes the shared-memory file of this process. The file itself is
+// left in place; remove it once both processes are done with it.
+func (sr *ShmRing[T]) Close() error {
+	err := syscall.Munmap(sr.data)
+	sr.data = nil
+	sr.slots = nil
+	if closeErr := sr.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
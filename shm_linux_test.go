@@ -0,0 +1,140 @@
+package ringbuffer
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestShmRing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "samples.shm")
+
+	producer, err := CreateShmRing[uint32](path, 4)
+	if err != nil {
+		t.Fatalf("failed to create shared-memory ring: %s", err)
+	}
+	defer producer.Close()
+	consumer, err := OpenShmRing[uint32](path)
+	if err != nil {
+		t.Fatalf("failed to open shared-memory ring: %s", err)
+	}
+	defer consumer.Close()
+
+	if consumer.Capacity() != 4 {
+		t.Errorf("incorrect capacity, expected %d but got %d", 4, consumer.Capacity())
+		t.Fail()
+	}
+	if _, err = consumer.Pop(); !errors.Is(err, ErrBufferEmpty) {
+		t.Errorf("incorrect error on Pop(), expected %v but got %v", ErrBufferEmpty, err)
+		t.Fail()
+	}
+	for i := uint32(1); i <= 4; i++ {
+		if err = producer.Write(i); err != nil {
+			t.Errorf("failed to write to buffer: %s", err)
+			t.Fail()
+		}
+	}
+	if err = producer.Write(5); !errors.Is(err, ErrBufferFull) {
+		t.Errorf("incorrect error on Write(), expected %v but got %v", ErrBufferFull, err)
+		t.Fail()
+	}
+
+	if value, err := consumer.Pop(); err != nil || value != 1 {
+		t.Errorf("incorrect result on Pop(), expected %d but got %d (%v)", 1, value, err)
+		t.Fail()
+	}
+	_ = producer.Write(5)
+	dst := make([]uint32, 8)
+	n := consumer.ReadMany(dst)
+	if !reflect.DeepEqual([]uint32{2, 3, 4, 5}, dst[:n]) {
+		t.Errorf("incorrect result on ReadMany(), expected %v but got %v", []uint32{2, 3, 4, 5}, dst[:n])
+		t.Fail()
+	}
+	if producer.Length() != 0 {
+		t.Errorf("incorrect length, expected %d but got %d", 0, producer.Length())
+		t.Fail()
+	}
+}
+
+func TestShmRingConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "samples.shm")
+	producer, _ := CreateShmRing[int64](path, 16)
+	defer producer.Close()
+	consumer, _ := OpenShmRing[int64](path)
+	defer consumer.Close()
+
+	const count = 10000
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := int64(0); i < count; {
+			if producer.Write(i) != nil {
+				runtime.Gosched() // the buffer is full, let the consumer catch up
+				continue
+			}
+			i++
+		}
+	}()
+
+	for expected := int64(0); expected < count; {
+		value, err := consumer.Pop()
+		if err != nil {
+			runtime.Gosched()
+			continue
+		}
+		if value != expected {
+			t.Fatalf("values arrived out of order, expected %d but got %d", expected, value)
+		}
+		expected++
+	}
+	wg.Wait()
+}
+
+func TestOpenShmRingTypeMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "samples.shm")
+	producer, _ := CreateShmRing[float64](path, 4)
+	defer producer.Close()
+	if _, err := OpenShmRing[int64](path); !errors.Is(err, ErrFileTypeMismatch) {
+		t.Errorf("incorrect error, expected %v but got %v", ErrFileTypeMismatch, err)
+		t.Fail()
+	}
+}
+
+func TestCreateShmRingReplace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "samples.shm")
+	old, _ := CreateShmRing[uint32](path, 4)
+	defer old.Close()
+	peer, err := OpenShmRing[uint32](path)
+	if err != nil {
+		t.Fatalf("failed to open shared-memory ring: %s", err)
+	}
+	defer peer.Close()
+	_ = old.Write(7)
+
+	// Recreating the file must not truncate the file the peer still has mapped, which
+	// would crash it with SIGBUS or reset its positions
+	replaced, err := CreateShmRing[uint32](path, 2)
+	if err != nil {
+		t.Fatalf("failed to replace shared-memory ring: %s", err)
+	}
+	defer replaced.Close()
+	if value, err := peer.Pop(); err != nil || value != 7 {
+		t.Errorf("incorrect result on Pop() from the old file, expected %d but got %d (%v)", 7, value, err)
+		t.Fail()
+	}
+
+	reopened, err := OpenShmRing[uint32](path)
+	if err != nil {
+		t.Fatalf("failed to open replaced shared-memory ring: %s", err)
+	}
+	defer reopened.Close()
+	if reopened.Capacity() != 2 || reopened.Length() != 0 {
+		t.Errorf("incorrect replaced ring, expected capacity %d and length %d but got %d and %d",
+			2, 0, reopened.Capacity(), reopened.Length())
+		t.Fail()
+	}
+}
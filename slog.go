@@ -0,0 +1,149 @@
+//go:build go1.21
+
+package ringbuffer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"math"
+	"sync"
+)
+
+// SlogOption configures a SlogHandler created by NewSlogHandler
+type SlogOption func(*slogOptions)
+
+// slogOptions collects the settings of every SlogOption passed to NewSlogHandler
+type slogOptions struct {
+	dumpLevel slog.Level
+	dumpTo    io.Writer
+}
+
+// WithDumpOnLevel makes the handler dump its recorded records to w whenever a record of
+// the given level or above is logged, e.g. slog.LevelError
+func WithDumpOnLevel(level slog.Level, w io.Writer) SlogOption {
+	return func(o *slogOptions) {
+		o.dumpLevel = level
+		o.dumpTo = w
+	}
+}
+
+// SlogHandler is a log/slog Handler that records the last log records in a ring buffer,
+// regardless of their level, before passing them on to another handler. This keeps the
+// debug records leading up to a failure in memory for post-mortem debugging without
+// writing them out.
+//
+// Records are stored as JSON objects with their time, level, message and attributes,
+// as written by slog.JSONHandler, and can be dumped on demand with Dump or
+// automatically with WithDumpOnLevel. Handlers derived with WithAttrs or WithGroup
+// record into the same ring buffer.
+type SlogHandler struct {
+	next   slog.Handler // May be nil to only record
+	record slog.Handler // Encodes records into shared.records
+	shared *slogShared
+}
+
+// slogShared is the state shared by a SlogHandler and every handler derived from it
+type slogShared struct {
+	records *RingBuffer[string]
+	opts    slogOptions
+	dumpMut sync.Mutex // Serializes dumps triggered by WithDumpOnLevel
+}
+
+// recordWriter stores every record written by a slog.JSONHandler in a ring buffer.
+// slog.JSONHandler writes each record with a single call to Write
+type recordWriter struct {
+	records *RingBuffer[string]
+}
+
+// Write implements io.Writer
+func (w recordWriter) Write(p []byte) (int, error) {
+	w.records.Write(string(bytes.TrimSuffix(p, []byte{'\n'})))
+	return len(p), nil
+}
+
+// NewSlogHandler creates a handler that records the last records log records and
+// passes every record on to next, if next is enabled for its level. next may be nil to
+// only record.
+func NewSlogHandler(next slog.Handler, records int, opts ...SlogOption) (*SlogHandler, error) {
+	rb, err := New[string](records)
+	if err != nil {
+		return nil, err
+	}
+	shared := &slogShared{records: rb}
+	for _, opt := range opts {
+		opt(&shared.opts)
+	}
+
+	record := slog.NewJSONHandler(recordWriter{records: rb}, &slog.HandlerOptions{
+		Level: slog.Level(math.MinInt), // Record every level
+	})
+	return &SlogHandler{next: next, record: record, shared: shared}, nil
+}
+
+// Enabled implements slog.Handler. It always returns true, as records of every level
+// are recorded.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return true
+}
+
+// Handle implements slog.Handler. It records r, passes it on to the next handler, and
+// dumps the recorded records if r is at or above the level set by WithDumpOnLevel.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	err := h.record.Handle(ctx, r)
+	if h.next != nil && h.next.Enabled(ctx, r.Level) {
+		if nextErr := h.next.Handle(ctx, r); nextErr != nil {
+			err = nextErr
+		}
+	}
+
+	if o := h.shared.opts; o.dumpTo != nil && r.Level >= o.dumpLevel {
+		h.shared.dumpMut.Lock()
+		defer h.shared.dumpMut.Unlock()
+		if dumpErr := h.Dump(o.dumpTo); err == nil {
+			err = dumpErr
+		}
+	}
+	return err
+}
+
+// WithAttrs implements slog.Handler. The new handler records into the same ring buffer.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.record = h.record.WithAttrs(attrs)
+	if h.next != nil {
+		h2.next = h.next.WithAttrs(attrs)
+	}
+	return &h2
+}
+
+// WithGroup implements slog.Handler. The new handler records into the same ring buffer.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.record = h.record.WithGroup(name)
+	if h.next != nil {
+		h2.next = h.next.WithGroup(name)
+	}
+	return &h2
+}
+
+// Records returns the recorded records as JSON objects, oldest first
+func (h *SlogHandler) Records() []string {
+	return h.shared.records.Read()
+}
+
+// Dump writes the recorded records to w as JSON lines, oldest first
+func (h *SlogHandler) Dump(w io.Writer) error {
+	var buf bytes.Buffer
+	h.shared.records.Snapshot().Range(func(i int, record string) bool {
+		buf.WriteString(record)
+		buf.WriteByte('\n')
+		return true
+	})
+	_, err := buf.WriteTo(w)
+	return err
+}
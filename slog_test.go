@@ -0,0 +1,87 @@
+//go:build go1.21
+
+package ringbuffer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewSlogHandler(t *testing.T) {
+	if _, err := NewSlogHandler(nil, 0); !errors.Is(err, ErrCapacityNegativeOrZero) {
+		t.Errorf("incorrect error, expected %v but got %v", ErrCapacityNegativeOrZero, err)
+		t.Fail()
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	var out bytes.Buffer
+	next := slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo})
+	h, _ := NewSlogHandler(next, 3)
+	logger := slog.New(h)
+
+	logger.Debug("skipped by next", "n", 1)
+	logger.With("request", "r1").WithGroup("db").Info("query", "rows", 2)
+	logger.Info("a")
+	logger.Info("b")
+
+	records := h.Records()
+	if len(records) != 3 {
+		t.Fatalf("incorrect number of records, expected 3 but got %d: %q", len(records), records)
+	}
+	var first map[string]any
+	if err := json.Unmarshal([]byte(records[0]), &first); err != nil {
+		t.Fatalf("failed to decode record %q: %s", records[0], err)
+	}
+	db, _ := first["db"].(map[string]any)
+	if first["msg"] != "query" || first["level"] != "INFO" || first["request"] != "r1" ||
+		db["rows"] != 2.0 || first["time"] == nil {
+		t.Errorf("incorrect record, got %v", first)
+		t.Fail()
+	}
+
+	// The next handler only sees the records it is enabled for
+	if strings.Contains(out.String(), "skipped") || strings.Count(out.String(), "\n") != 3 {
+		t.Errorf("incorrect output of the next handler, got %q", out.String())
+		t.Fail()
+	}
+
+	var dump bytes.Buffer
+	if err := h.Dump(&dump); err != nil {
+		t.Fatalf("failed to dump: %s", err)
+	}
+	if dump.String() != strings.Join(records, "\n")+"\n" {
+		t.Errorf("incorrect dump, expected the records as lines but got %q", dump.String())
+		t.Fail()
+	}
+}
+
+func TestSlogHandlerDumpOnLevel(t *testing.T) {
+	var dump bytes.Buffer
+	h, _ := NewSlogHandler(nil, 10, WithDumpOnLevel(slog.LevelError, &dump))
+	logger := slog.New(h)
+
+	logger.Debug("connecting")
+	logger.Warn("slow")
+	if dump.Len() != 0 {
+		t.Errorf("nothing should be dumped below the dump level, got %q", dump.String())
+		t.Fail()
+	}
+
+	logger.Error("failed")
+	lines := strings.Split(strings.TrimSuffix(dump.String(), "\n"), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], `"msg":"connecting"`) ||
+		!strings.Contains(lines[2], `"level":"ERROR"`) {
+		t.Errorf("incorrect dump, got %q", dump.String())
+		t.Fail()
+	}
+	if !h.Enabled(context.Background(), slog.LevelDebug-10) {
+		t.Errorf("the handler should be enabled for every level")
+		t.Fail()
+	}
+}
@@ -0,0 +1,101 @@
+package ringbuffer
+
+import "time"
+
+// Snapshot is an immutable, consistent view of a RingBuffer at the time Snapshot() was
+// called. A snapshot never observes later writes to the buffer and needs no locking, so
+// it can be handed to long-running readers without holding up writers.
+type Snapshot[T BufferType] struct {
+	buffer       []T         // Backing array shared with the buffer until its next write
+	stamps       []time.Time // Write times shared with the buffer; nil without a clock
+	capacity     int
+	elementCount int
+	writeIndex   int
+}
+
+// Snapshot returns an immutable view of the buffer in O(1) time. Rather than copying
+// the data up front, the buffer and the snapshot share the same backing array, and the
+// buffer copies it (copy-on-write) before its next mutation. That copy replaces a slice
+// passed to WithBackingSlice for good.
+func (rb *RingBuffer[T]) Snapshot() *Snapshot[T] {
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+
+	rb.shared = true
+	return &Snapshot[T]{
+		buffer:       rb.buffer,
+		stamps:       rb.stamps,
+		capacity:     rb.capacity,
+		elementCount: rb.elementCount,
+		writeIndex:   rb.writeIndex,
+	}
+}
+
+// detach gives the buffer its own copy of the backing array if it is shared with a
+// Snapshot. It must be called with rb.mut held before any write into rb.buffer
+func (rb *RingBuffer[T]) detach() {
+	if !rb.shared {
+		return
+	}
+	buffer := make([]T, len(rb.buffer))
+	copy(buffer, rb.buffer)
+	rb.buffer = buffer
+	if rb.stamps != nil {
+		stamps := make([]time.Time, len(rb.stamps))
+		copy(stamps, rb.stamps)
+		rb.stamps = stamps
+	}
+	rb.shared = false
+}
+
+// Len returns the number of elements or values within the snapshot
+func (s *Snapshot[T]) Len() int {
+	return s.elementCount
+}
+
+// At returns the element at logical index i, where index 0 is the oldest element. An
+// error is returned if i is out of range.
+func (s *Snapshot[T]) At(i int) (T, error) {
+	if i < 0 || i >= s.elementCount {
+		var zero T
+		return zero, ErrIndexOutOfRange
+	}
+	return s.buffer[s.index(i)], nil
+}
+
+// Range calls fn for each element of the snapshot in "First-In First-Out" (FIFO) order,
+// passing the logical index and the value. Iteration stops early if fn returns false.
+func (s *Snapshot[T]) Range(fn func(i int, value T) bool) {
+	for i := 0; i < s.elementCount; i++ {
+		if !fn(i, s.buffer[s.index(i)]) {
+			return
+		}
+	}
+}
+
+// Read returns the contents of the snapshot in "First-In First-Out" (FIFO) order
+func (s *Snapshot[T]) Read() []T {
+	result := make([]T, 0, s.elementCount)
+	for i := 0; i < s.elementCount; i++ {
+		result = append(result, s.buffer[s.index(i)])
+	}
+	return result
+}
+
+// Timestamps returns the time every element of the snapshot was written in "First-In
+// First-Out" (FIFO) order. It returns nil unless the buffer was created with WithClock.
+func (s *Snapshot[T]) Timestamps() []time.Time {
+	if s.stamps == nil {
+		return nil
+	}
+	result := make([]time.Time, 0, s.elementCount)
+	for i := 0; i < s.elementCount; i++ {
+		result = append(result, s.stamps[s.index(i)])
+	}
+	return result
+}
+
+// index maps a logical index onto the backing array
+func (s *Snapshot[T]) index(i int) int {
+	return (s.writeIndex + s.capacity - s.elementCount + i) % s.capacity
+}
@@ -0,0 +1,71 @@
+package ringbuffer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	rb, _ := New[string](3)
+	if err := rb.WriteMany([]string{"a", "b", "c"}); err != nil {
+		t.Errorf("failed to write to buffer: %s", err)
+		t.Fail()
+	}
+	snap := rb.Snapshot()
+	expected := []string{"a", "b", "c"}
+
+	// Writes after the snapshot must not be observed by it
+	rb.Write("d")
+	rb.Reset()
+	rb.Write("e")
+
+	t.Run("Read()", func(t *testing.T) {
+		if !reflect.DeepEqual(expected, snap.Read()) {
+			t.Errorf("incorrect result on Read(), expected %s but got %s", expected, snap.Read())
+			t.Fail()
+		}
+		if snap.Len() != len(expected) {
+			t.Errorf("incorrect length, expected %d but got %d", len(expected), snap.Len())
+			t.Fail()
+		}
+	})
+	t.Run("At()", func(t *testing.T) {
+		for i, value := range expected {
+			if v, err := snap.At(i); err != nil || v != value {
+				t.Errorf("incorrect result on At(%d), expected %s but got %s (%v)", i, value, v, err)
+				t.Fail()
+			}
+		}
+		if _, err := snap.At(len(expected)); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("an error was expected for an out of range index but got %v", err)
+			t.Fail()
+		}
+	})
+	t.Run("Range()", func(t *testing.T) {
+		var result []string
+		snap.Range(func(i int, value string) bool {
+			result = append(result, value)
+			return i < 1
+		})
+		if !reflect.DeepEqual(expected[:2], result) {
+			t.Errorf("incorrect result on Range(), expected %s but got %s", expected[:2], result)
+			t.Fail()
+		}
+	})
+	t.Run("copy on write", func(t *testing.T) {
+		rb, _ := New[int](2)
+		rb.Write(1)
+		snap := rb.Snapshot()
+		rb.Write(2)
+		rb.Write(3)
+		if !reflect.DeepEqual([]int{1}, snap.Read()) {
+			t.Errorf("snapshot observed a later write, expected %v but got %v", []int{1}, snap.Read())
+			t.Fail()
+		}
+		if !reflect.DeepEqual([]int{2, 3}, rb.Read()) {
+			t.Errorf("incorrect result on Read(), expected %v but got %v", []int{2, 3}, rb.Read())
+			t.Fail()
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package ringbuffer
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"sync"
+)
+
+// Error handling statements for SpillRing
+var (
+	ErrSpillSizeNegativeOrZero = errors.New("failed to create a new spill ring! The " +
+		"maximum spill file size must be greater than zero")
+	ErrSpillFull = errors.New("failed to write to spill ring! The spill file has " +
+		"reached its maximum size")
+)
+
+// spillRecordSize is the size of the little-endian length in front of every spilled value
+const spillRecordSize = 4
+
+// SpillRing is a ring buffer with a second, on-disk tier. Instead of overwriting the
+// oldest element when the in-memory ring is full, the oldest element is moved to a
+// bounded spill file, and Pop reads it back before any element still in memory. This
+// buffers bursts without losing data while keeping memory usage bounded.
+//
+// The spill file is used as a circular queue of at most maxBytes bytes, so space is
+// reused as soon as spilled elements have been read back. It is truncated whenever it
+// has been read back completely, and its contents do not survive Close.
+type SpillRing[T BufferType] struct {
+	mut      sync.Mutex
+	memory   *RingBuffer[T]
+	file     *os.File
+	maxBytes int64
+	head     int64 // Logical offset of the oldest record; the file offset is head % maxBytes
+	tail     int64 // Logical offset the next record is written to
+	spilled  int   // Number of records between head and tail
+}
+
+// NewSpillRing creates a spill ring that keeps up to capacity elements in memory and
+// spills older elements into the file at path, up to maxBytes bytes. The file is
+// created or truncated.
+func NewSpillRing[T BufferType](capacity int, path string, maxBytes int64) (*SpillRing[T], error) {
+	if maxBytes <= 0 {
+		return nil, ErrSpillSizeNegativeOrZero
+	}
+	memory, err := New[T](capacity)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &SpillRing[T]{memory: memory, file: file, maxBytes: maxBytes}, nil
+}
+
+// Write inserts one element into the ring. If the in-memory ring is full, its oldest
+// element is first moved to the spill file. An error is returned, and nothing is
+// written, if the spill file would grow beyond its maximum size.
+func (s *SpillRing[T]) Write(value T) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.memory.IsFull() {
+		oldest, err := s.memory.PeekFront()
+		if err != nil {
+			return err
+		}
+		if err := s.spill(oldest); err != nil {
+			return err
+		}
+		if _, err := s.memory.PopFront(); err != nil {
+			return err
+		}
+	}
+	return s.memory.PushBack(value)
+}
+
+// spill appends value to the spill file. It must be called with s.mut held
+func (s *SpillRing[T]) spill(value T) error {
+	record := make([]byte, spillRecordSize, spillRecordSize+16)
+	record = appendValue(record, value)
+	binary.LittleEndian.PutUint32(record, uint32(len(record)-spillRecordSize))
+
+	if s.tail-s.head+int64(len(record)) > s.maxBytes {
+		return ErrSpillFull
+	}
+	if err := s.writeAt(record, s.tail); err != nil {
+		return err
+	}
+	s.tail += int64(len(record))
+	s.spilled++
+	return nil
+}
+
+// Pop removes and returns the oldest element, reading it back from the spill file if any
+// elements were spilled. An error is returned if the ring is empty.
+func (s *SpillRing[T]) Pop() (T, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.spilled == 0 {
+		return s.memory.PopFront()
+	}
+
+	var zero T
+	var length [spillRecordSize]byte
+	if err := s.readAt(length[:], s.head); err != nil {
+		return zero, err
+	}
+	data := make([]byte, binary.LittleEndian.Uint32(length[:]))
+	if err := s.readAt(data, s.head+spillRecordSize); err != nil {
+		return zero, err
+	}
+	value, err := parseValue[T](string(data))
+	if err != nil {
+		return zero, err
+	}
+
+	s.head += spillRecordSize + int64(len(data))
+	s.spilled--
+	if s.spilled == 0 {
+		if err := s.file.Truncate(0); err != nil {
+			return zero, err
+		}
+		s.head, s.tail = 0, 0
+	}
+	return value, nil
+}
+
+// writeAt writes b at the logical offset off, wrapping around the end of the file. It
+// must be called with s.mut held
+func (s *SpillRing[T]) writeAt(b []byte, off int64) error {
+	pos := off % s.maxBytes
+	n := min64(int64(len(b)), s.maxBytes-pos)
+	if _, err := s.file.WriteAt(b[:n], pos); err != nil {
+		return err
+	}
+	if n < int64(len(b)) {
+		_, err := s.file.WriteAt(b[n:], 0)
+		return err
+	}
+	return nil
+}
+
+// readAt fills b from the logical offset off, wrapping around the end of the file. It
+// must be called with s.mut held
+func (s *SpillRing[T]) readAt(b []byte, off int64) error {
+	pos := off % s.maxBytes
+	n := min64(int64(len(b)), s.maxBytes-pos)
+	if _, err := s.file.ReadAt(b[:n], pos); err != nil {
+		return err
+	}
+	if n < int64(len(b)) {
+		_, err := s.file.ReadAt(b[n:], 0)
+		return err
+	}
+	return nil
+}
+
+// min64 returns the smaller of a and b
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Length returns the number of elements in memory and in the spill file combined
+func (s *SpillRing[T]) Length() int {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	return s.memory.Length() + s.spilled
+}
+
+// Spilled returns the number of elements currently held in the spill file
+func (s *SpillRing[T]) Spilled() int {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	return s.spilled
+}
+
+// SpillBytes returns the number of bytes currently used in the spill file
+func (s *SpillRing[T]) SpillBytes() int64 {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	return s.tail - s.head
+}
+
+// Close closes and removes the spill file. Spilled elements that have not been read
+// back are lost.
+func (s *SpillRing[T]) Close() error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	name := s.file.Name()
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
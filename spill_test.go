@@ -0,0 +1,178 @@
+package ringbuffer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewSpillRingErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		maxBytes int64
+		expected error
+	}{
+		{"zero capacity", 0, 1024, ErrCapacityNegativeOrZero},
+		{"zero size", 4, 0, ErrSpillSizeNegativeOrZero},
+		{"valid", 4, 1024, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "spill")
+			s, err := NewSpillRing[int](test.capacity, path, test.maxBytes)
+			if !errors.Is(err, test.expected) {
+				t.Errorf("incorrect error, expected %v but got %v", test.expected, err)
+				t.Fail()
+			}
+			if err == nil {
+				s.Close()
+			}
+		})
+	}
+}
+
+// popAll pops every element until the spill ring is empty
+func popAll[T BufferType](t *testing.T, s *SpillRing[T]) []T {
+	var result []T
+	for s.Length() > 0 {
+		value, err := s.Pop()
+		if err != nil {
+			t.Fatalf("failed to pop: %s", err)
+		}
+		result = append(result, value)
+	}
+	return result
+}
+
+func TestSpillRing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spill")
+	s, err := NewSpillRing[string](3, path, 1024)
+	if err != nil {
+		t.Fatalf("failed to create spill ring: %s", err)
+	}
+	defer s.Close()
+
+	for _, v := range []string{"a", "b", "c", "d", "e"} {
+		if err := s.Write(v); err != nil {
+			t.Fatalf("failed to write %q: %s", v, err)
+		}
+	}
+	if s.Length() != 5 || s.Spilled() != 2 {
+		t.Errorf("incorrect length, expected 5 with 2 spilled but got %d with %d spilled",
+			s.Length(), s.Spilled())
+		t.Fail()
+	}
+
+	// Writes between pops must still come out in order
+	first, _ := s.Pop()
+	if err := s.Write("f"); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+	result := append([]string{first}, popAll(t, s)...)
+	expected := []string{"a", "b", "c", "d", "e", "f"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("incorrect contents, expected %v but got %v", expected, result)
+		t.Fail()
+	}
+
+	if s.SpillBytes() != 0 {
+		t.Errorf("incorrect spill bytes, expected 0 but got %d", s.SpillBytes())
+		t.Fail()
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Size() != 0 {
+		t.Errorf("the drained spill file was not truncated (%v)", err)
+		t.Fail()
+	}
+	if _, err := s.Pop(); !errors.Is(err, ErrBufferEmpty) {
+		t.Errorf("incorrect error, expected %v but got %v", ErrBufferEmpty, err)
+		t.Fail()
+	}
+}
+
+func TestSpillRingFull(t *testing.T) {
+	// Room for exactly two spilled single digit values
+	s, err := NewSpillRing[int](2, filepath.Join(t.TempDir(), "spill"), 2*(spillRecordSize+1))
+	if err != nil {
+		t.Fatalf("failed to create spill ring: %s", err)
+	}
+	defer s.Close()
+
+	for v := 1; v <= 4; v++ {
+		if err := s.Write(v); err != nil {
+			t.Fatalf("failed to write %d: %s", v, err)
+		}
+	}
+	if err := s.Write(5); !errors.Is(err, ErrSpillFull) {
+		t.Errorf("incorrect error, expected %v but got %v", ErrSpillFull, err)
+		t.Fail()
+	}
+
+	result := popAll(t, s)
+	expected := []int{1, 2, 3, 4}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("incorrect contents after a rejected write, expected %v but got %v",
+			expected, result)
+		t.Fail()
+	}
+}
+
+func TestSpillRingClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spill")
+	s, err := NewSpillRing[float64](1, path, 1024)
+	if err != nil {
+		t.Fatalf("failed to create spill ring: %s", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("failed to close: %s", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("the spill file was not removed on close (%v)", err)
+		t.Fail()
+	}
+}
+
+func TestSpillRingReuse(t *testing.T) {
+	const maxBytes = 64
+	path := filepath.Join(t.TempDir(), "spill")
+	s, err := NewSpillRing[string](1, path, maxBytes)
+	if err != nil {
+		t.Fatalf("failed to create spill ring: %s", err)
+	}
+	defer s.Close()
+
+	// Keep a backlog spilled while writing many times maxBytes in total, with values of
+	// varying length so that records wrap around the end of the file at every offset
+	var written, result []string
+	total := 0
+	for i := 0; total < 20*maxBytes; i++ {
+		v := strings.Repeat(string(rune('a'+i%26)), 1+i%7)
+		if err := s.Write(v); err != nil {
+			t.Fatalf("failed to write %q after %d bytes with %d spilled (%d bytes): %s",
+				v, total, s.Spilled(), s.SpillBytes(), err)
+		}
+		written = append(written, v)
+		total += spillRecordSize + len(v)
+		if s.Spilled() > 3 {
+			value, err := s.Pop()
+			if err != nil {
+				t.Fatalf("failed to pop: %s", err)
+			}
+			result = append(result, value)
+		}
+		if info, err := os.Stat(path); err != nil || info.Size() > maxBytes {
+			t.Fatalf("the spill file grew beyond %d bytes (%v)", maxBytes, err)
+		}
+	}
+
+	result = append(result, popAll(t, s)...)
+	if !reflect.DeepEqual(result, written) {
+		t.Errorf("incorrect contents, expected %v but got %v", written, result)
+		t.Fail()
+	}
+}
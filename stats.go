@@ -0,0 +1,86 @@
+package ringbuffer
+
+import "math"
+
+// Stats holds summary statistics over the values of a numeric ring buffer window
+type Stats struct {
+	Count int     // Number of values the statistics were computed over
+	Sum   float64 // Sum of all values
+	Mean  float64 // Arithmetic mean of all values
+	Min   float64 // Smallest value
+	Max   float64 // Largest value
+}
+
+// Stats computes the count, sum, mean, minimum and maximum of the values currently
+// stored within the buffer.
+//
+// An error is returned if the buffer type is not numeric (bool or string) or if the
+// buffer contains no values.
+func (rb *RingBuffer[T]) Stats() (Stats, error) {
+	rb.mut.RLock()
+	defer rb.mut.RUnlock()
+	return rb.stats()
+}
+
+// stats does the work for Stats() and must be called with rb.mut held
+func (rb *RingBuffer[T]) stats() (Stats, error) {
+	var zero T
+	if _, ok := toFloat64(zero); !ok {
+		return Stats{}, ErrNotNumeric
+	}
+	if rb.elementCount == 0 {
+		return Stats{}, ErrBufferEmpty
+	}
+
+	s := Stats{Min: math.Inf(1), Max: math.Inf(-1)}
+	for i := 0; i < rb.elementCount; i++ {
+		value, _ := toFloat64(rb.buffer[rb.index(i)])
+		s.add(value)
+	}
+	s.Mean = s.Sum / float64(s.Count)
+	return s, nil
+}
+
+// add folds a single value into the count, sum, minimum and maximum. Mean is left to
+// the caller since it only needs to be computed once
+func (s *Stats) add(value float64) {
+	s.Count++
+	s.Sum += value
+	if value < s.Min {
+		s.Min = value
+	}
+	if value > s.Max {
+		s.Max = value
+	}
+}
+
+// toFloat64 converts a numeric BufferType value to a float64. The boolean is false if
+// the value is not numeric (bool or string)
+func toFloat64[T BufferType](value T) (float64, bool) {
+	switch v := any(value).(type) {
+	case int:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case byte:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,40 @@
+package ringbuffer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	t.Run("numeric", func(t *testing.T) {
+		rb, _ := New[int](3)
+		if _, err := rb.Stats(); !errors.Is(err, ErrBufferEmpty) {
+			t.Errorf("an error was expected for an empty buffer but got %v", err)
+			t.Fail()
+		}
+		if err := rb.WriteMany([]int{5, 1, 3}); err != nil {
+			t.Errorf("failed to write to buffer: %s", err)
+			t.Fail()
+		}
+		rb.Write(7)
+
+		s, err := rb.Stats()
+		if err != nil {
+			t.Errorf("an error was not expected when computing stats: %s", err)
+			t.Fail()
+		}
+		expected := Stats{Count: 3, Sum: 11, Mean: 11.0 / 3, Min: 1, Max: 7}
+		if s != expected {
+			t.Errorf("incorrect stats, expected %+v but got %+v", expected, s)
+			t.Fail()
+		}
+	})
+	t.Run("not numeric", func(t *testing.T) {
+		rb, _ := New[string](3)
+		rb.Write("a")
+		if _, err := rb.Stats(); !errors.Is(err, ErrNotNumeric) {
+			t.Errorf("an error was expected for a string buffer but got %v", err)
+			t.Fail()
+		}
+	})
+}
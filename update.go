@@ -0,0 +1,53 @@
+package ringbuffer
+
+// Set replaces the element at logical index i, where index 0 is the oldest element,
+// with value. An error is returned if i is out of range.
+//
+// Updating an element does not change its position in the buffer or the time it was
+// written, as recorded by WithClock. If the buffer has a byte budget and the new value no
+// longer fits, the elements older than it are evicted the same way Write does, which
+// lowers its logical index by the number of evicted elements. Newer elements are never
+// evicted, so ErrBufferFull is returned and nothing changes if evicting all older
+// elements is not enough.
+func (rb *RingBuffer[T]) Set(i int, value T) error {
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+
+	if i < 0 || i >= rb.elementCount {
+		return ErrIndexOutOfRange
+	}
+	return rb.replace(rb.index(i), value)
+}
+
+// Update replaces the element at logical index i with the result of calling fn with the
+// current element. An error is returned if i is out of range.
+//
+// fn is called while the buffer is locked, so it must not call methods on the same
+// buffer.
+func (rb *RingBuffer[T]) Update(i int, fn func(value T) T) error {
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+
+	if i < 0 || i >= rb.elementCount {
+		return ErrIndexOutOfRange
+	}
+	index := rb.index(i)
+	return rb.replace(index, fn(rb.buffer[index]))
+}
+
+// CompareAndSwap replaces the element at logical index i with new only if it currently
+// equals old, and reports whether the swap happened. It returns false if i is out of
+// range, or if new does not fit within the byte budget of the buffer.
+func (rb *RingBuffer[T]) CompareAndSwap(i int, old, new T) bool {
+	rb.mut.Lock()
+	defer rb.mut.Unlock()
+
+	if i < 0 || i >= rb.elementCount {
+		return false
+	}
+	index := rb.index(i)
+	if rb.buffer[index] != old {
+		return false
+	}
+	return rb.replace(index, new) == nil
+}
@@ -0,0 +1,83 @@
+package ringbuffer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		index    int
+		expected []int
+		err      error
+	}{
+		{"negative index", -1, []int{1, 2, 3, 4, 5}, ErrIndexOutOfRange},
+		{"index too large", 5, []int{1, 2, 3, 4, 5}, ErrIndexOutOfRange},
+		{"oldest", 0, []int{9, 2, 3, 4, 5}, nil},
+		{"across wrap", 3, []int{1, 2, 3, 9, 5}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rb := newWrapped(t)
+			if err := rb.Set(test.index, 9); !errors.Is(err, test.err) {
+				t.Errorf("incorrect error on Set(), expected %v but got %v", test.err, err)
+				t.Fail()
+			}
+			if !reflect.DeepEqual(test.expected, rb.Read()) {
+				t.Errorf("incorrect result on Read(), expected %v but got %v", test.expected, rb.Read())
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	rb := newWrapped(t)
+	snap := rb.Snapshot()
+	if err := rb.Update(4, func(value int) int { return value * 10 }); err != nil {
+		t.Errorf("an error was not expected on Update(): %s", err)
+		t.Fail()
+	}
+	if err := rb.Update(5, func(value int) int { return value }); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("incorrect error on Update(), expected %v but got %v", ErrIndexOutOfRange, err)
+		t.Fail()
+	}
+	if !reflect.DeepEqual([]int{1, 2, 3, 4, 50}, rb.Read()) {
+		t.Errorf("incorrect result on Read(), expected %v but got %v", []int{1, 2, 3, 4, 50}, rb.Read())
+		t.Fail()
+	}
+	if !reflect.DeepEqual([]int{1, 2, 3, 4, 5}, snap.Read()) {
+		t.Errorf("snapshot observed a later update, expected %v but got %v", []int{1, 2, 3, 4, 5}, snap.Read())
+		t.Fail()
+	}
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	tests := []struct {
+		name     string
+		index    int
+		old      int
+		expected bool
+	}{
+		{"match", 1, 2, true},
+		{"mismatch", 1, 3, false},
+		{"out of range", 5, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rb := newWrapped(t)
+			if swapped := rb.CompareAndSwap(test.index, test.old, 20); swapped != test.expected {
+				t.Errorf("incorrect result on CompareAndSwap(), expected %v but got %v", test.expected, swapped)
+				t.Fail()
+			}
+			if value := rb.Read()[1]; test.expected && value != 20 {
+				t.Errorf("incorrect value after swap, expected %d but got %d", 20, value)
+				t.Fail()
+			}
+		})
+	}
+}